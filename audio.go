@@ -0,0 +1,389 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"unsafe"
+)
+
+const sdlInitAudioFlag uint32 = 0x00000010
+
+// AudioFormat (https://wiki.libsdl.org/SDL_AudioFormat)
+//
+// Samples are always in native byte order.
+type AudioFormat uint16
+
+const (
+	AudioS16 AudioFormat = 0x8010
+	AudioF32 AudioFormat = 0x8120
+)
+
+const (
+	sdlAudioMaskEndian = 0x1000
+	sdlAudioMaskBits   = 0xff
+)
+
+// Size returns the size of a sample in bytes.
+func (f AudioFormat) Size() int {
+	return int(f&sdlAudioMaskBits) / 8
+}
+
+var nativeAudioEndian = func() AudioFormat {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 0 {
+		return sdlAudioMaskEndian
+	}
+	return 0
+}()
+
+// AudioSpec describes the audio stream of a device, samples of all channels
+// are interleaved.
+type AudioSpec struct {
+	// Freq is the sample rate, the default is 48000.
+	Freq int
+	// Format is the sample format, the default is AudioS16.
+	Format AudioFormat
+	// Channels is the number of channels, the default is 2.
+	Channels int
+	// Samples is the buffer size in sample frames, it must be a power of two
+	// no larger than 32768. The default is 1024.
+	Samples int
+}
+
+// FrameSize returns the size in bytes of one sample for all channels.
+func (s AudioSpec) FrameSize() int {
+	return s.Format.Size() * s.Channels
+}
+
+func (s *AudioSpec) setDefaults() {
+	if s.Freq == 0 {
+		s.Freq = 48000
+	}
+	if s.Format == 0 {
+		s.Format = AudioS16
+	}
+	if s.Channels == 0 {
+		s.Channels = 2
+	}
+	if s.Samples == 0 {
+		s.Samples = 1024
+	}
+}
+
+// sdlAudioSpec (https://wiki.libsdl.org/SDL_AudioSpec)
+type sdlAudioSpec struct {
+	Freq     int32
+	Format   uint16
+	Channels uint8
+	Silence  uint8
+	Samples  uint16
+	_        uint16
+	Size     uint32
+	Callback uintptr
+	Userdata uintptr
+}
+
+var desiredAudioSpec, obtainedAudioSpec sdlAudioSpec
+
+// AudioCallback is called from the audio thread to fill buf with samples. It
+// must not block and must not call other vsdl functions.
+type AudioCallback func(buf []byte)
+
+// AudioDevice (https://wiki.libsdl.org/CategoryAudio)
+type AudioDevice struct {
+	// The counters are first to be 64-bit aligned for atomic access.
+	underruns uint64
+	overruns  uint64
+
+	id       uint32
+	deviceID uint32
+	spec     AudioSpec
+	silence  uint8
+	callback AudioCallback
+
+	lock   sync.Mutex
+	cond   *sync.Cond
+	ring   []byte
+	r, n   int
+	closed bool
+
+	queued  bool
+	capture bool
+	paused  bool
+
+	queuedData           uint32
+	watchStop, watchDone chan struct{}
+}
+
+var (
+	audioDevices     = map[uintptr]*AudioDevice{}
+	audioDevicesLock sync.Mutex
+	audioDeviceNext  uintptr
+)
+
+// ConfigWithAudio initializes the audio subsystem, this is required for
+// OpenAudioDevice.
+func ConfigWithAudio() Config {
+	return func() error {
+		if windowConfig.newWindow {
+			return errGlobalConfig
+		}
+		initFlags |= sdlInitAudioFlag
+		return nil
+	}
+}
+
+// AudioDevices returns the names of the audio output devices.
+func AudioDevices() ([]string, error) {
+	return audioDeviceNames(false)
+}
+
+func audioDeviceNames(capture bool) ([]string, error) {
+	var names []string
+	err := sendCommand(false, func() error {
+		n := sdlGetNumAudioDevices(capture)
+		for i := 0; i < n; i++ {
+			names = append(names, sdlGetAudioDeviceName(i, capture))
+		}
+		return nil
+	})
+	return names, err
+}
+
+// OpenAudioDevice opens an audio output device, an empty name selects the
+// default device. If cb is nil the device is fed by writing to it. The device
+// is paused when opened.
+func OpenAudioDevice(name string, spec AudioSpec, cb AudioCallback) (*AudioDevice, error) {
+	d := &AudioDevice{callback: cb}
+	if cb == nil {
+		d.callback = d.pull
+	}
+
+	if err := d.open(name, false, spec, true); err != nil {
+		return nil, err
+	}
+
+	if cb == nil {
+		d.cond = sync.NewCond(&d.lock)
+		d.ring = make([]byte, 4*d.spec.Samples*d.spec.FrameSize())
+	}
+	return d, nil
+}
+
+func (d *AudioDevice) open(name string, capture bool, spec AudioSpec, callback bool) error {
+	spec.setDefaults()
+	if spec.Format != AudioS16 && spec.Format != AudioF32 {
+		return errors.New("invalid audio format")
+	}
+	// SDL stores the buffer size in 16 bits.
+	if spec.Samples <= 0 || spec.Samples > 1<<15 || spec.Samples&(spec.Samples-1) != 0 {
+		return errors.New("audio buffer size must be a power of two up to 32768")
+	}
+
+	audioDevicesLock.Lock()
+	audioDeviceNext++
+	userdata := audioDeviceNext
+	audioDevices[userdata] = d
+	audioDevicesLock.Unlock()
+
+	err := sendCommand(false, func() error {
+		desiredAudioSpec = sdlAudioSpec{
+			Freq:     int32(spec.Freq),
+			Format:   uint16(spec.Format | nativeAudioEndian),
+			Channels: uint8(spec.Channels),
+			Samples:  uint16(spec.Samples),
+		}
+		if callback {
+			desiredAudioSpec.Callback = sdlAudioCallback()
+			desiredAudioSpec.Userdata = userdata
+		}
+
+		if d.id = sdlOpenAudioDevice(name, capture, unsafe.Pointer(&desiredAudioSpec), unsafe.Pointer(&obtainedAudioSpec)); d.id == 0 {
+			return sdlToGoError()
+		}
+
+		d.deviceID = d.id
+		d.paused = true
+		d.silence = obtainedAudioSpec.Silence
+		d.spec = AudioSpec{
+			Freq:     int(obtainedAudioSpec.Freq),
+			Format:   AudioFormat(obtainedAudioSpec.Format) &^ sdlAudioMaskEndian,
+			Channels: int(obtainedAudioSpec.Channels),
+			Samples:  int(obtainedAudioSpec.Samples),
+		}
+		return nil
+	})
+
+	if err != nil {
+		audioDevicesLock.Lock()
+		delete(audioDevices, userdata)
+		audioDevicesLock.Unlock()
+	}
+	return err
+}
+
+// audioCallback dispatches the SDL audio callback to the device identified by
+// userdata.
+func audioCallback(userdata uintptr, buf []byte) {
+	audioDevicesLock.Lock()
+	d := audioDevices[userdata]
+	audioDevicesLock.Unlock()
+
+	if d == nil {
+		for i := range buf {
+			buf[i] = 0
+		}
+		return
+	}
+	d.callback(buf)
+}
+
+// ID identifies the device in AudioDeviceRemovedEvent.
+func (d *AudioDevice) ID() uint32 {
+	return d.deviceID
+}
+
+// Spec returns the format of the opened device.
+func (d *AudioDevice) Spec() AudioSpec {
+	return d.spec
+}
+
+func (d *AudioDevice) Pause() error {
+	return d.pause(true)
+}
+
+func (d *AudioDevice) Resume() error {
+	return d.pause(false)
+}
+
+func (d *AudioDevice) pause(b bool) error {
+	return sendCommand(false, func() error {
+		if d.id == 0 {
+			return errors.New("audio device is closed")
+		}
+		sdlPauseAudioDevice(d.id, b)
+		d.paused = b
+
+		if d.queued {
+			if b {
+				d.stopQueueWatch()
+			} else {
+				d.startQueueWatch()
+			}
+		}
+		return nil
+	})
+}
+
+// Close closes the device, pending writes return io.ErrClosedPipe.
+func (d *AudioDevice) Close() error {
+	err := sendCommand(false, func() error {
+		if d.id == 0 {
+			return errors.New("audio device is closed")
+		}
+		d.stopQueueWatch()
+		sdlCloseAudioDevice(d.id)
+		d.id = 0
+		return nil
+	})
+
+	audioDevicesLock.Lock()
+	for k, v := range audioDevices {
+		if v == d {
+			delete(audioDevices, k)
+		}
+	}
+	audioDevicesLock.Unlock()
+
+	d.lock.Lock()
+	d.closed = true
+	if d.cond != nil {
+		d.cond.Broadcast()
+	}
+	d.lock.Unlock()
+	return err
+}
+
+// Write queues interleaved samples for playback when the device was opened
+// without a callback. It blocks while the internal buffer, of four device
+// buffers, is full. The length of p must be a multiple of the frame size.
+func (d *AudioDevice) Write(p []byte) (int, error) {
+	if d.ring == nil || d.capture {
+		return 0, errors.New("audio device is not writable")
+	}
+	if len(p)%d.spec.FrameSize() != 0 {
+		return 0, errors.New("length is not a multiple of the frame size")
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	var written int
+	for len(p) > 0 {
+		for d.n == len(d.ring) && !d.closed {
+			d.cond.Wait()
+		}
+		if d.closed {
+			return written, io.ErrClosedPipe
+		}
+
+		n := d.writeRing(p)
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// pull fills buf from the ring buffer, and with silence if there is not
+// enough data.
+func (d *AudioDevice) pull(buf []byte) {
+	d.lock.Lock()
+	n := d.readRing(buf)
+	d.cond.Broadcast()
+	d.lock.Unlock()
+
+	for i := n; i < len(buf); i++ {
+		buf[i] = d.silence
+	}
+}
+
+// writeRing copies as much as fits of p to the ring buffer.
+func (d *AudioDevice) writeRing(p []byte) int {
+	var written int
+	for len(p) > 0 && d.n < len(d.ring) {
+		w := (d.r + d.n) % len(d.ring)
+		end := len(d.ring)
+		if w < d.r {
+			end = d.r
+		}
+
+		n := copy(d.ring[w:end], p)
+		d.n += n
+		written += n
+		p = p[n:]
+	}
+	return written
+}
+
+// readRing copies as much as is available from the ring buffer to p.
+func (d *AudioDevice) readRing(p []byte) int {
+	var read int
+	for len(p) > 0 && d.n > 0 {
+		end := d.r + d.n
+		if end > len(d.ring) {
+			end = len(d.ring)
+		}
+
+		n := copy(p, d.ring[d.r:end])
+		d.r = (d.r + n) % len(d.ring)
+		d.n -= n
+		read += n
+		p = p[n:]
+	}
+	return read
+}
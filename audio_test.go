@@ -0,0 +1,15 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import "testing"
+
+func TestOpenAudioDeviceInvalidSamples(t *testing.T) {
+	for _, n := range []int{-1024, 1000, 1<<15 + 1, 1 << 16, 1 << 17} {
+		if _, err := OpenAudioDevice("", AudioSpec{Samples: n}, nil); err == nil {
+			t.Errorf("expected an error for a buffer size of %d", n)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// +build !windows,!headless
+
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+/*
+#include <SDL.h>
+*/
+import "C"
+import "unsafe"
+
+// The preamble of a file with exported functions can only contain
+// declarations, so the callback is kept separate from vsdl_unix.go.
+
+//export vsdlAudioCallback
+func vsdlAudioCallback(userdata unsafe.Pointer, stream *C.Uint8, length C.int) {
+	audioCallback(uintptr(userdata), (*[1 << 30]byte)(unsafe.Pointer(stream))[:length:length])
+}
@@ -0,0 +1,78 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// CaptureDevices returns the names of the audio capture devices.
+func CaptureDevices() ([]string, error) {
+	return audioDeviceNames(true)
+}
+
+// OpenCaptureDevice opens an audio capture device, an empty name selects the
+// default device. Captured samples are read with Read, samples that do not
+// fit in the internal buffer, of eight device buffers, are dropped. The device
+// is paused when opened.
+func OpenCaptureDevice(name string, spec AudioSpec) (*AudioDevice, error) {
+	d := &AudioDevice{capture: true}
+	d.callback = d.push
+
+	if err := d.open(name, true, spec, true); err != nil {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	d.cond = sync.NewCond(&d.lock)
+	d.ring = make([]byte, 8*d.spec.Samples*d.spec.FrameSize())
+	d.lock.Unlock()
+	return d, nil
+}
+
+// Read reads captured interleaved samples, it blocks until samples are
+// available and returns io.EOF when the device is closed.
+func (d *AudioDevice) Read(p []byte) (int, error) {
+	if !d.capture {
+		return 0, errors.New("audio device is not readable")
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for d.n == 0 && !d.closed {
+		d.cond.Wait()
+	}
+	if d.n == 0 {
+		return 0, io.EOF
+	}
+	return d.readRing(p), nil
+}
+
+// push stores captured samples in the ring buffer.
+func (d *AudioDevice) push(buf []byte) {
+	d.lock.Lock()
+	if d.ring == nil {
+		d.lock.Unlock()
+		return
+	}
+
+	n := d.writeRing(buf)
+	d.cond.Broadcast()
+	d.lock.Unlock()
+
+	if n < len(buf) {
+		atomic.AddUint64(&d.overruns, 1)
+	}
+}
+
+// Overruns returns the number of times captured samples were dropped because
+// they were not read in time.
+func (d *AudioDevice) Overruns() uint64 {
+	return atomic.LoadUint64(&d.overruns)
+}
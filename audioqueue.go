@@ -0,0 +1,159 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// OpenQueuedAudioDevice opens an audio output device that is fed with Queue,
+// which suits code that produces audio together with each video frame. The
+// device is paused when opened.
+func OpenQueuedAudioDevice(name string, spec AudioSpec) (*AudioDevice, error) {
+	d := &AudioDevice{queued: true}
+	if err := d.open(name, false, spec, false); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Queue appends interleaved samples to the playback queue. While the device is
+// playing the queue is checked every device buffer period, if it ran empty
+// it is counted as an underrun and an AudioUnderrunEvent is sent.
+func (d *AudioDevice) Queue(p []byte) error {
+	if len(p) == 0 {
+		return nil
+	}
+
+	return sendCommand(false, func() error {
+		if d.id == 0 {
+			return errors.New("audio device is closed")
+		}
+		if !d.queued {
+			return errors.New("audio device is not queued")
+		}
+
+		atomic.StoreUint32(&d.queuedData, 1)
+		if sdlQueueAudio(d.id, unsafe.Pointer(&p[0]), len(p)) {
+			return sdlToGoError()
+		}
+		return nil
+	})
+}
+
+// QueuedSize returns the number of bytes in the playback queue.
+func (d *AudioDevice) QueuedSize() (int, error) {
+	var n int
+	err := sendCommand(false, func() error {
+		if d.id == 0 {
+			return errors.New("audio device is closed")
+		}
+		n = sdlGetQueuedAudioSize(d.id)
+		return nil
+	})
+	return n, err
+}
+
+// Latency returns the time until samples queued now are played, the queued
+// samples plus one device buffer.
+func (d *AudioDevice) Latency() (time.Duration, error) {
+	n, err := d.QueuedSize()
+	if err != nil {
+		return 0, err
+	}
+
+	frames := n/d.spec.FrameSize() + d.spec.Samples
+	return time.Duration(frames) * time.Second / time.Duration(d.spec.Freq), nil
+}
+
+// ClearQueue drops all queued samples.
+func (d *AudioDevice) ClearQueue() error {
+	return sendCommand(false, func() error {
+		if d.id == 0 {
+			return errors.New("audio device is closed")
+		}
+		// The watch is restarted so the cleared queue is not counted as
+		// an underrun.
+		d.stopQueueWatch()
+		sdlClearQueuedAudio(d.id)
+		atomic.StoreUint32(&d.queuedData, 0)
+		if !d.paused {
+			d.startQueueWatch()
+		}
+		return nil
+	})
+}
+
+// Underruns returns the number of times the playback queue ran empty.
+func (d *AudioDevice) Underruns() uint64 {
+	return atomic.LoadUint64(&d.underruns)
+}
+
+// startQueueWatch starts checking the queue of a playing device for
+// underruns, it must be called on the main thread.
+func (d *AudioDevice) startQueueWatch() {
+	if d.watchStop != nil {
+		return
+	}
+
+	d.watchStop, d.watchDone = make(chan struct{}), make(chan struct{})
+	period := time.Duration(d.spec.Samples) * time.Second / time.Duration(d.spec.Freq)
+	go d.watchQueue(d.id, period, d.watchStop, d.watchDone)
+}
+
+// stopQueueWatches stops the queue watch of all devices before SDL is shut
+// down.
+func stopQueueWatches() {
+	audioDevicesLock.Lock()
+	defer audioDevicesLock.Unlock()
+
+	for _, d := range audioDevices {
+		d.stopQueueWatch()
+	}
+}
+
+func (d *AudioDevice) stopQueueWatch() {
+	if d.watchStop == nil {
+		return
+	}
+
+	close(d.watchStop)
+	<-d.watchDone
+	d.watchStop, d.watchDone = nil, nil
+}
+
+// watchQueue runs on its own goroutine and reports an underrun when the queue
+// is empty after it had samples, either at the previous check or queued since.
+// SDL_GetQueuedAudioSize is thread-safe.
+func (d *AudioDevice) watchQueue(id uint32, period time.Duration, stop, done chan struct{}) {
+	defer close(done)
+
+	t := time.NewTicker(period)
+	defer t.Stop()
+
+	var filled bool
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+
+		queued := atomic.SwapUint32(&d.queuedData, 0) != 0
+		if sdlGetQueuedAudioSize(id) > 0 {
+			filled = true
+			continue
+		}
+
+		if filled || queued {
+			filled = false
+			atomic.AddUint64(&d.underruns, 1)
+			postEvent(newAudioUnderrunEvent(d.deviceID))
+		}
+	}
+}
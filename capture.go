@@ -0,0 +1,111 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"image"
+	"unsafe"
+)
+
+// FrameHook is called with every presented frame, on the main thread after the
+// frame has been uploaded to the back-buffer. The image is only valid during
+// the call and must be copied if it is retained. The hook must not call
+// functions of this package.
+type FrameHook func(frame image.Image)
+
+func ConfigWithFrameHook(h FrameHook) Config {
+	return func() error {
+		windowConfig.frameHook = h
+		return nil
+	}
+}
+
+// SetFrameHook replaces the current frame hook, nil removes it.
+func SetFrameHook(h FrameHook) {
+	if defaultWindow != nil {
+		defaultWindow.SetFrameHook(h)
+	}
+}
+
+func (w *Window) SetFrameHook(h FrameHook) {
+	w.frameHookLock.Lock()
+	w.frameHook = h
+	w.frameHookLock.Unlock()
+}
+
+func (w *Window) frameHookFunc() FrameHook {
+	w.frameHookLock.Lock()
+	defer w.frameHookLock.Unlock()
+	return w.frameHook
+}
+
+// callFrameHook calls h with frame on the main thread, indexed frames get the
+// palette of the back-buffer.
+func (w *Window) callFrameHook(h FrameHook, frame image.Image) {
+	if h == nil {
+		return
+	}
+
+	if p, ok := frame.(*image.Paletted); ok && w.format == PixelFormatIndex8 {
+		frame = &image.Paletted{
+			Pix:     p.Pix,
+			Stride:  p.Stride,
+			Rect:    p.Rect,
+			Palette: w.devicePalette,
+		}
+	}
+	h(frame)
+}
+
+var screenshotSize [2]int32
+
+// Screenshot reads back the content of the window, as it is displayed and at
+// the resolution of the window, including the scaling to the logical size.
+func Screenshot() (*image.RGBA, error) {
+	if defaultWindow == nil {
+		return nil, errWindowClosed
+	}
+	return defaultWindow.Screenshot()
+}
+
+func (w *Window) Screenshot() (*image.RGBA, error) {
+	var img *image.RGBA
+
+	err := sendCommand(false, func() error {
+		if w.texture == 0 {
+			return errWindowClosed
+		}
+		if w.lockedFrame != nil {
+			return errFrameLocked
+		}
+
+		if sdlGetRendererOutputSize(w.renderer, unsafe.Pointer(&screenshotSize[0]), unsafe.Pointer(&screenshotSize[1])) {
+			return sdlToGoError()
+		}
+
+		size := image.Pt(int(screenshotSize[0]), int(screenshotSize[1]))
+		img = image.NewRGBA(image.Rectangle{Max: size})
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 0xff
+		}
+
+		// The content of the back-buffer is undefined after a present so the
+		// texture is copied again before reading it back.
+		if sdlRenderCopy(w.renderer, w.texture) {
+			return sdlToGoError()
+		}
+
+		updateRect = newSDLRect(img.Rect)
+		if sdlRenderReadPixels(w.renderer, unsafe.Pointer(&updateRect), pixelFormatABGR8888, unsafe.Pointer(&img.Pix[0]), uintptr(img.Stride)) {
+			return sdlToGoError()
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return img, nil
+}
@@ -0,0 +1,229 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+)
+
+const sdlInitGameControllerFlag uint32 = 0x00002000
+
+// ControllerAxis (https://wiki.libsdl.org/SDL_GameControllerAxis)
+type ControllerAxis uint8
+
+const (
+	ControllerLeftX ControllerAxis = iota
+	ControllerLeftY
+	ControllerRightX
+	ControllerRightY
+	ControllerTriggerLeft
+	ControllerTriggerRight
+)
+
+// ControllerButton (https://wiki.libsdl.org/SDL_GameControllerButton)
+type ControllerButton uint8
+
+const (
+	ControllerA ControllerButton = iota
+	ControllerB
+	ControllerX
+	ControllerY
+	ControllerBack
+	ControllerGuide
+	ControllerStart
+	ControllerLeftStick
+	ControllerRightStick
+	ControllerLeftShoulder
+	ControllerRightShoulder
+	ControllerDPadUp
+	ControllerDPadDown
+	ControllerDPadLeft
+	ControllerDPadRight
+)
+
+// ConfigWithGameControllers initializes the game controller subsystem, this
+// is required for controller events and OpenController.
+func ConfigWithGameControllers() Config {
+	return func() error {
+		if windowConfig.newWindow {
+			return errGlobalConfig
+		}
+		initFlags |= sdlInitGameControllerFlag
+		return nil
+	}
+}
+
+// Controller (https://wiki.libsdl.org/CategoryGameController)
+type Controller struct {
+	handle uintptr
+	id     int32
+	name   string
+	guid   string
+}
+
+// NumJoysticks returns the number of attached joysticks, including the ones
+// that are not recognized as game controllers.
+func NumJoysticks() (int, error) {
+	var n int
+	err := sendCommand(false, func() error {
+		if n = sdlNumJoysticks(); n < 0 {
+			return sdlToGoError()
+		}
+		return nil
+	})
+	return n, err
+}
+
+// IsGameController reports if the joystick at device index has a game
+// controller mapping.
+func IsGameController(index int) (bool, error) {
+	var b bool
+	err := sendCommand(false, func() error {
+		b = sdlIsGameController(index)
+		return nil
+	})
+	return b, err
+}
+
+// OpenController opens the game controller at device index. The index is the
+// same as in ControllerDeviceAddedEvent.Which.
+func OpenController(index int) (*Controller, error) {
+	c := new(Controller)
+	err := sendCommand(false, func() error {
+		if c.handle = sdlGameControllerOpen(index); c.handle == 0 {
+			return sdlToGoError()
+		}
+
+		joystick := sdlGameControllerGetJoystick(c.handle)
+		c.id = sdlJoystickInstanceID(joystick)
+		c.name = sdlGameControllerName(c.handle)
+
+		guid := sdlJoystickGetGUID(joystick)
+		c.guid = hex.EncodeToString(guid[:])
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Controller) Close() error {
+	return sendCommand(false, func() error {
+		if c.handle == 0 {
+			return errors.New("controller is closed")
+		}
+		sdlGameControllerClose(c.handle)
+		c.handle = 0
+		return nil
+	})
+}
+
+// InstanceID identifies the controller in controller events.
+func (c *Controller) InstanceID() int32 {
+	return c.id
+}
+
+func (c *Controller) Name() string {
+	return c.name
+}
+
+// GUID returns the joystick GUID as used in controller mappings.
+func (c *Controller) GUID() string {
+	return c.guid
+}
+
+func (c *Controller) Button(b ControllerButton) (bool, error) {
+	var pressed bool
+	err := sendCommand(false, func() error {
+		if c.handle == 0 {
+			return errors.New("controller is closed")
+		}
+		pressed = sdlGameControllerGetButton(c.handle, b)
+		return nil
+	})
+	return pressed, err
+}
+
+func (c *Controller) Axis(a ControllerAxis) (int16, error) {
+	var v int16
+	err := sendCommand(false, func() error {
+		if c.handle == 0 {
+			return errors.New("controller is closed")
+		}
+		v = sdlGameControllerGetAxis(c.handle, a)
+		return nil
+	})
+	return v, err
+}
+
+var sdlPlatformNames = map[string]string{
+	"windows": "Windows",
+	"darwin":  "Mac OS X",
+	"linux":   "Linux",
+	"android": "Android",
+	"freebsd": "FreeBSD",
+	"netbsd":  "NetBSD",
+	"openbsd": "OpenBSD",
+}
+
+// LoadControllerMappings adds the mappings in r, in the format of
+// gamecontrollerdb.txt. Mappings for other platforms are skipped, unless the
+// platform is unknown to vsdl, then all mappings are added. It returns the
+// number of added or updated mappings, and an error if r has no mappings for
+// the platform.
+func LoadControllerMappings(r io.Reader) (int, error) {
+	var mappings []string
+	name, known := sdlPlatformNames[runtime.GOOS]
+	platform := "platform:" + name + ","
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if known && strings.Contains(line, "platform:") && !strings.Contains(line+",", platform) {
+			continue
+		}
+		mappings = append(mappings, line)
+	}
+
+	if err := s.Err(); err != nil {
+		return 0, err
+	}
+	if len(mappings) == 0 {
+		return 0, errors.New("no controller mappings for this platform")
+	}
+
+	var n int
+	err := sendCommand(false, func() error {
+		for _, m := range mappings {
+			if sdlGameControllerAddMapping(m) < 0 {
+				return sdlToGoError()
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+func LoadControllerMappingsFromFile(name string) (int, error) {
+	fp, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer fp.Close()
+	return LoadControllerMappings(fp)
+}
@@ -0,0 +1,138 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"errors"
+	"image"
+	"unsafe"
+)
+
+// DisplayMode (https://wiki.libsdl.org/SDL_DisplayMode)
+type DisplayMode struct {
+	Size        image.Point
+	RefreshRate int
+	// Format is the SDL pixel format (https://wiki.libsdl.org/SDL_PixelFormatEnum).
+	Format uint32
+}
+
+// Display describes a monitor. Index identifies the display in
+// ConfigWithDisplay.
+type Display struct {
+	Index int
+	Name  string
+
+	// Bounds is the area of the display in the desktop coordinate space,
+	// UsableBounds excludes task bars and docks.
+	Bounds, UsableBounds image.Rectangle
+
+	// The diagonal, horizontal and vertical DPI. They are zero if the
+	// platform can not report them.
+	DiagonalDPI, HorizontalDPI, VerticalDPI float32
+
+	DesktopMode DisplayMode
+	Modes       []DisplayMode
+}
+
+var (
+	displayBounds sdlRect
+	displayDPI    [3]float32
+)
+
+// Displays returns the connected displays, the modes of each display are
+// sorted from the largest to the smallest.
+func Displays() ([]Display, error) {
+	var displays []Display
+
+	err := sendCommand(false, func() error {
+		n := sdlGetNumVideoDisplays()
+		if n < 0 {
+			return sdlToGoError()
+		}
+
+		for i := 0; i < n; i++ {
+			d := Display{Index: i, Name: sdlGetDisplayName(i)}
+
+			if sdlGetDisplayBounds(i, unsafe.Pointer(&displayBounds)) {
+				return sdlToGoError()
+			}
+			d.Bounds = displayBounds.toRectangle()
+
+			d.UsableBounds = d.Bounds
+			if !sdlGetDisplayUsableBounds(i, unsafe.Pointer(&displayBounds)) {
+				d.UsableBounds = displayBounds.toRectangle()
+			}
+
+			displayDPI = [3]float32{}
+			if !sdlGetDisplayDPI(i, unsafe.Pointer(&displayDPI[0]), unsafe.Pointer(&displayDPI[1]), unsafe.Pointer(&displayDPI[2])) {
+				d.DiagonalDPI, d.HorizontalDPI, d.VerticalDPI = displayDPI[0], displayDPI[1], displayDPI[2]
+			}
+
+			if sdlGetDesktopDisplayMode(i, unsafe.Pointer(&displayMode)) {
+				return sdlToGoError()
+			}
+			d.DesktopMode = displayMode.toDisplayMode()
+
+			for j := 0; j < sdlGetNumDisplayModes(i); j++ {
+				if sdlGetDisplayMode(i, j, unsafe.Pointer(&displayMode)) {
+					return sdlToGoError()
+				}
+				d.Modes = append(d.Modes, displayMode.toDisplayMode())
+			}
+			displays = append(displays, d)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return displays, nil
+}
+
+// ConfigWithDisplay opens the window on the display with index, see Displays.
+func ConfigWithDisplay(index int) Config {
+	return func() error {
+		if index < 0 {
+			return errors.New("invalid display index")
+		}
+		windowConfig.display = index
+		return nil
+	}
+}
+
+// ConfigWithDisplayMode selects the display mode closest to m for exclusive
+// fullscreen, and makes ToggleFullscreen use exclusive instead of desktop
+// fullscreen. The closest mode is the smallest one at least as large as m, see
+// SDL_GetClosestDisplayMode. A zero size in m means the window size, a zero
+// format or refresh rate the one of the desktop mode. Creating the window
+// fails if the display has no such mode.
+func ConfigWithDisplayMode(m DisplayMode) Config {
+	return func() error {
+		windowConfig.displayMode = &m
+		return nil
+	}
+}
+
+func (r sdlRect) toRectangle() image.Rectangle {
+	return image.Rect(int(r.X), int(r.Y), int(r.X+r.W), int(r.Y+r.H))
+}
+
+func (m sdlDisplayMode) toDisplayMode() DisplayMode {
+	return DisplayMode{
+		Size:        image.Pt(int(m.W), int(m.H)),
+		RefreshRate: int(m.RefreshRate),
+		Format:      m.Format,
+	}
+}
+
+func (m DisplayMode) toSDL() sdlDisplayMode {
+	return sdlDisplayMode{
+		Format:      m.Format,
+		W:           int32(m.Size.X),
+		H:           int32(m.Size.Y),
+		RefreshRate: int32(m.RefreshRate),
+	}
+}
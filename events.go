@@ -5,18 +5,41 @@
 package vsdl
 
 import (
+	"fmt"
+	"image"
 	"sync"
 	"unsafe"
 )
 
 const maxEvents = 4096
 
+// pendingEvents holds the events generated by vsdl, they are returned by
+// pollEvent before the SDL events.
+var pendingEvents = make(chan Event, maxEvents)
+
+// postEvent queues an event generated by vsdl, it can be called from any
+// goroutine. The event is dropped if the queue is full.
+func postEvent(ev Event) {
+	select {
+	case pendingEvents <- ev:
+	default:
+		ev.Release()
+		log.Println("event queue overflow")
+	}
+}
+
 func pollEvent() Event {
+	select {
+	case ev := <-pendingEvents:
+		return ev
+	default:
+	}
+
 	ev := eventPool.Get().(*sdlEvent)
 	up := unsafe.Pointer(ev)
 	aev := (*anyEvent)(up)
 
-	if !sdlPollEvent(uintptr(up)) {
+	if !sdlPollEvent(up) {
 		aev.Release()
 		return nil
 	}
@@ -25,17 +48,37 @@ func pollEvent() Event {
 	case sdlQuitEventType:
 		return (*QuitEvent)(up)
 	case sdlWindowEventType:
-		return (*WindowEvent)(up)
+		ev := (*WindowEvent)(up)
+		handleWindowEvent(ev)
+		return ev
 	case sdlKeyDownEventType:
 		return (*KeyDownEvent)(up)
 	case sdlKeyUpEventType:
 		return (*KeyUpEvent)(up)
+	case sdlTextEditingEventType:
+		return (*TextEditingEvent)(up)
+	case sdlTextInputEventType:
+		return (*TextInputEvent)(up)
 	case sdlMouseMotionEventType:
 		return (*MouseMotionEvent)(up)
 	case sdlMouseButtonDownEventType, sdlMouseButtonUpEventType:
 		return (*MouseButtonEvent)(up)
 	case sdlMouseWheelEventType:
 		return (*MouseWheelEvent)(up)
+	case sdlControllerAxisMotionEventType:
+		return (*ControllerAxisEvent)(up)
+	case sdlControllerButtonDownEventType, sdlControllerButtonUpEventType:
+		return (*ControllerButtonEvent)(up)
+	case sdlControllerDeviceAddedEventType:
+		return (*ControllerDeviceAddedEvent)(up)
+	case sdlControllerDeviceRemovedEventType:
+		return (*ControllerDeviceRemovedEvent)(up)
+	case sdlControllerDeviceRemappedEventType:
+		return (*ControllerDeviceRemappedEvent)(up)
+	case sdlAudioDeviceAddedEventType:
+		return (*AudioDeviceAddedEvent)(up)
+	case sdlAudioDeviceRemovedEventType:
+		return (*AudioDeviceRemovedEvent)(up)
 	default:
 		aev.Release()
 		return nil
@@ -50,6 +93,8 @@ const (
 const (
 	sdlKeyDownEventType = 0x300 + iota
 	sdlKeyUpEventType
+	sdlTextEditingEventType
+	sdlTextInputEventType
 )
 
 const (
@@ -59,6 +104,24 @@ const (
 	sdlMouseWheelEventType
 )
 
+const (
+	sdlControllerAxisMotionEventType = 0x650 + iota
+	sdlControllerButtonDownEventType
+	sdlControllerButtonUpEventType
+	sdlControllerDeviceAddedEventType
+	sdlControllerDeviceRemovedEventType
+	sdlControllerDeviceRemappedEventType
+)
+
+const (
+	sdlAudioDeviceAddedEventType = 0x1100 + iota
+	sdlAudioDeviceRemovedEventType
+)
+
+// The user event range of SDL is used for the events generated by vsdl, they
+// are never registered with SDL.
+const audioUnderrunEventType = 0x8000
+
 const sdlEventMaxSize = 56
 
 type sdlEvent [sdlEventMaxSize]byte
@@ -88,67 +151,179 @@ type QuitEvent struct {
 type WindowEvent struct {
 	anyEvent
 
-	_     uint32
-	_     uint32
-	Event uint8
-	_     uint8
-	_     uint8
-	_     uint8
-	Data1 int32
-	Data2 int32
+	_        uint32
+	WindowID uint32
+	Event    WindowEventID
+	_        uint8
+	_        uint8
+	_        uint8
+	Data1    int32
+	Data2    int32
+}
+
+// WindowEventID (https://wiki.libsdl.org/SDL_WindowEventID)
+type WindowEventID uint8
+
+const (
+	WindowShown WindowEventID = 1 + iota
+	WindowHidden
+	WindowExposed
+	// WindowMoved has the new position, see WindowEvent.Position.
+	WindowMoved
+	// WindowResized is sent when the window is resized by the user or the
+	// window manager, it is preceded by WindowSizeChanged.
+	WindowResized
+	// WindowSizeChanged is sent for every size change, see WindowEvent.Size.
+	WindowSizeChanged
+	WindowMinimized
+	WindowMaximized
+	WindowRestored
+	// WindowEnter and WindowLeave are sent when the mouse enters and leaves
+	// the window.
+	WindowEnter
+	WindowLeave
+	// WindowFocusGained and WindowFocusLost are sent when the window gains and
+	// loses keyboard focus.
+	WindowFocusGained
+	WindowFocusLost
+	// WindowClose is a request from the window manager to close the window.
+	WindowClose
+	WindowTakeFocus
+	WindowHitTest
+)
+
+var windowEventNames = [...]string{
+	"None",
+	"Shown",
+	"Hidden",
+	"Exposed",
+	"Moved",
+	"Resized",
+	"SizeChanged",
+	"Minimized",
+	"Maximized",
+	"Restored",
+	"Enter",
+	"Leave",
+	"FocusGained",
+	"FocusLost",
+	"Close",
+	"TakeFocus",
+	"HitTest",
+}
+
+func (id WindowEventID) String() string {
+	if int(id) < len(windowEventNames) {
+		return windowEventNames[id]
+	}
+	return fmt.Sprintf("WindowEventID(%d)", id)
+}
+
+// Position returns the new window position of a WindowMoved event.
+func (e *WindowEvent) Position() image.Point {
+	return image.Pt(int(e.Data1), int(e.Data2))
+}
+
+// Size returns the new window size of a WindowResized or WindowSizeChanged
+// event.
+func (e *WindowEvent) Size() image.Point {
+	return image.Pt(int(e.Data1), int(e.Data2))
 }
 
 type KeyDownEvent struct {
 	anyEvent
 
-	_      uint32
-	_      uint32
-	State  uint8
-	Repeat uint8
-	_      uint8
-	_      uint8
-	Keysym Keysym
+	_        uint32
+	WindowID uint32
+	State    uint8
+	Repeat   uint8
+	_        uint8
+	_        uint8
+	Keysym   Keysym
 }
 
 type KeyUpEvent struct {
 	anyEvent
 
-	_      uint32
-	_      uint32
-	State  uint8
-	Repeat uint8
-	_      uint8
-	_      uint8
-	Keysym Keysym
+	_        uint32
+	WindowID uint32
+	State    uint8
+	Repeat   uint8
+	_        uint8
+	_        uint8
+	Keysym   Keysym
+}
+
+const sdlTextEditingEventTextSize = 32
+
+// TextEditingEvent (https://wiki.libsdl.org/SDL_TextEditingEvent)
+type TextEditingEvent struct {
+	anyEvent
+
+	_        uint32
+	WindowID uint32
+	text     [sdlTextEditingEventTextSize]byte
+	Start    int32
+	Length   int32
+}
+
+// Text returns the UTF-8 encoded composition text.
+func (e *TextEditingEvent) Text() string {
+	return cStringToGo(e.text[:])
+}
+
+const sdlTextInputEventTextSize = 32
+
+// TextInputEvent (https://wiki.libsdl.org/SDL_TextInputEvent)
+type TextInputEvent struct {
+	anyEvent
+
+	_        uint32
+	WindowID uint32
+	text     [sdlTextInputEventTextSize]byte
+}
+
+// Text returns the UTF-8 encoded input text.
+func (e *TextInputEvent) Text() string {
+	return cStringToGo(e.text[:])
+}
+
+func cStringToGo(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
 }
 
 // MouseMotionEvent (https://wiki.libsdl.org/SDL_MouseMotionEvent)
 type MouseMotionEvent struct {
 	anyEvent
 
-	_     uint32
-	_     uint32
-	Which uint32
-	State uint32
-	X     int32
-	Y     int32
-	XRel  int32
-	YRel  int32
+	_        uint32
+	WindowID uint32
+	Which    uint32
+	State    uint32
+	X        int32
+	Y        int32
+	XRel     int32
+	YRel     int32
 }
 
 // MouseButtonEvent (https://wiki.libsdl.org/SDL_MouseButtonEvent)
 type MouseButtonEvent struct {
 	anyEvent
 
-	_      uint32
-	_      uint32
-	Which  uint32
-	Button uint8
-	State  uint8
-	_      uint8
-	_      uint8
-	X      int32
-	Y      int32
+	_        uint32
+	WindowID uint32
+	Which    uint32
+	Button   uint8
+	State    uint8
+	_        uint8
+	_        uint8
+	X        int32
+	Y        int32
 }
 
 // MouseWheelEvent (https://wiki.libsdl.org/SDL_MouseWheelEvent)
@@ -156,9 +331,108 @@ type MouseWheelEvent struct {
 	anyEvent
 
 	_         uint32
-	_         uint32
+	WindowID  uint32
 	Which     uint32
 	X         int32
 	Y         int32
 	Direction uint32
 }
+
+// ControllerAxisEvent (https://wiki.libsdl.org/SDL_ControllerAxisEvent)
+type ControllerAxisEvent struct {
+	anyEvent
+
+	_     uint32
+	Which int32
+	Axis  ControllerAxis
+	_     uint8
+	_     uint8
+	_     uint8
+	Value int16
+	_     uint16
+}
+
+// ControllerButtonEvent (https://wiki.libsdl.org/SDL_ControllerButtonEvent)
+type ControllerButtonEvent struct {
+	anyEvent
+
+	_      uint32
+	Which  int32
+	Button ControllerButton
+	State  uint8
+	_      uint8
+	_      uint8
+}
+
+// ControllerDeviceAddedEvent (https://wiki.libsdl.org/SDL_ControllerDeviceEvent)
+//
+// Which is the device index to pass to OpenController.
+type ControllerDeviceAddedEvent struct {
+	anyEvent
+
+	_     uint32
+	Which int32
+}
+
+// ControllerDeviceRemovedEvent (https://wiki.libsdl.org/SDL_ControllerDeviceEvent)
+//
+// Which is the instance id of the removed controller.
+type ControllerDeviceRemovedEvent struct {
+	anyEvent
+
+	_     uint32
+	Which int32
+}
+
+// ControllerDeviceRemappedEvent (https://wiki.libsdl.org/SDL_ControllerDeviceEvent)
+type ControllerDeviceRemappedEvent struct {
+	anyEvent
+
+	_     uint32
+	Which int32
+}
+
+// AudioDeviceAddedEvent (https://wiki.libsdl.org/SDL_AudioDeviceEvent)
+//
+// Which is the device index, as in AudioDevices or CaptureDevices.
+type AudioDeviceAddedEvent struct {
+	anyEvent
+
+	_         uint32
+	Which     uint32
+	IsCapture uint8
+	_         uint8
+	_         uint8
+	_         uint8
+}
+
+// AudioDeviceRemovedEvent (https://wiki.libsdl.org/SDL_AudioDeviceEvent)
+//
+// Which is the id of an opened device, see AudioDevice.ID.
+type AudioDeviceRemovedEvent struct {
+	anyEvent
+
+	_         uint32
+	Which     uint32
+	IsCapture uint8
+	_         uint8
+	_         uint8
+	_         uint8
+}
+
+// AudioUnderrunEvent is sent when the playback queue of a device opened with
+// OpenQueuedAudioDevice runs empty while the device is playing.
+//
+// Which is the id of the device, see AudioDevice.ID.
+type AudioUnderrunEvent struct {
+	anyEvent
+
+	_     uint32
+	Which uint32
+}
+
+func newAudioUnderrunEvent(which uint32) *AudioUnderrunEvent {
+	ev := (*AudioUnderrunEvent)(unsafe.Pointer(eventPool.Get().(*sdlEvent)))
+	*ev = AudioUnderrunEvent{anyEvent: audioUnderrunEventType, Which: which}
+	return ev
+}
@@ -0,0 +1,312 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"unsafe"
+)
+
+// PixelFormat selects the memory layout of the back-buffer and the image type
+// accepted by Present.
+type PixelFormat int
+
+const (
+	// PixelFormatABGR8888 is the default format and accepts any image.Image,
+	// *image.RGBA is uploaded without conversion.
+	PixelFormatABGR8888 PixelFormat = iota
+	// PixelFormatIndex8 is selected by ConfigWithPalette and accepts *image.Paletted.
+	PixelFormatIndex8
+	// PixelFormatARGB8888 accepts *BGRA.
+	PixelFormatARGB8888
+	// PixelFormatRGB888 accepts *BGRA, the alpha channel is ignored.
+	PixelFormatRGB888
+	// PixelFormatRGB565 accepts *RGB565.
+	PixelFormatRGB565
+	// PixelFormatYV12 accepts *image.YCbCr with 4:2:0 subsampling.
+	PixelFormatYV12
+	// PixelFormatIYUV accepts *image.YCbCr with 4:2:0 subsampling.
+	PixelFormatIYUV
+	// PixelFormatNV12 accepts *NV12.
+	PixelFormatNV12
+)
+
+var sdlPixelFormats = map[PixelFormat]uint32{
+	PixelFormatABGR8888: pixelFormatABGR8888,
+	PixelFormatIndex8:   pixelFormatABGR8888,
+	PixelFormatARGB8888: pixelFormatARGB8888,
+	PixelFormatRGB888:   pixelFormatRGB888,
+	PixelFormatRGB565:   pixelFormatRGB565,
+	PixelFormatYV12:     pixelFormatYV12,
+	PixelFormatIYUV:     pixelFormatIYUV,
+	PixelFormatNV12:     pixelFormatNV12,
+}
+
+// ConfigWithPixelFormat sets the format of the back-buffer texture, which
+// decides the image type accepted by Present. Images of other types are
+// converted, except for the YUV and indexed formats.
+func ConfigWithPixelFormat(f PixelFormat) Config {
+	return func() error {
+		if _, ok := sdlPixelFormats[f]; !ok {
+			return errors.New("unsupported pixel format")
+		}
+		windowConfig.format = f
+		return nil
+	}
+}
+
+// toBackBufferFormat returns img in the Go image type matching the back-buffer
+// format, converting the regions into a reusable scratch image when possible.
+func (w *Window) toBackBufferFormat(img image.Image, regions []image.Rectangle) (image.Image, error) {
+	switch w.format {
+	case PixelFormatABGR8888:
+		return w.toRGBA(img, regions), nil
+	case PixelFormatIndex8:
+		if _, ok := img.(*image.Paletted); ok {
+			return img, nil
+		}
+		return nil, errors.New("invalid image format, expected *image.Paletted")
+	case PixelFormatARGB8888, PixelFormatRGB888:
+		if _, ok := img.(*BGRA); ok {
+			return img, nil
+		}
+		return w.toScratchImage(img, regions, func(r image.Rectangle) draw.Image { return NewBGRA(r) }), nil
+	case PixelFormatRGB565:
+		if _, ok := img.(*RGB565); ok {
+			return img, nil
+		}
+		return w.toScratchImage(img, regions, func(r image.Rectangle) draw.Image { return NewRGB565(r) }), nil
+	case PixelFormatNV12:
+		if _, ok := img.(*NV12); ok {
+			return img, nil
+		}
+		return w.toScratchImage(img, regions, func(r image.Rectangle) draw.Image { return NewNV12(r) }), nil
+	case PixelFormatYV12, PixelFormatIYUV:
+		if ycc, ok := img.(*image.YCbCr); ok && ycc.SubsampleRatio == image.YCbCrSubsampleRatio420 {
+			return img, nil
+		}
+		return nil, errors.New("invalid image format, expected *image.YCbCr with 4:2:0 subsampling")
+	default:
+		return nil, errors.New("invalid image format")
+	}
+}
+
+func (w *Window) toScratchImage(img image.Image, regions []image.Rectangle, alloc func(image.Rectangle) draw.Image) draw.Image {
+	b := img.Bounds()
+	if w.scratchImage == nil || w.scratchImage.Bounds().Size() != b.Size() {
+		w.scratchImage = alloc(image.Rectangle{Max: b.Size()})
+	}
+	drawRegions(w.scratchImage, img, regions)
+	return w.scratchImage
+}
+
+// updateFrame uploads the regions of frame, which must be in the back-buffer
+// format, to the texture.
+func (w *Window) updateFrame(frame image.Image, regions []image.Rectangle) error {
+	if p, ok := frame.(*image.Paletted); ok {
+		return w.updateIndexBuffer(p, regions)
+	}
+
+	// NV12 frames are uploaded in full, see updateTexture, so it is only
+	// done once no matter how many regions changed.
+	if _, ok := frame.(*NV12); ok {
+		return w.updateTexture(frame, regions[0])
+	}
+
+	for _, r := range regions {
+		if err := w.updateTexture(frame, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var updateRect sdlRect
+
+// updateTexture uploads the region r, given relative to the image bounds, of
+// img to the same region of the texture.
+func (w *Window) updateTexture(img image.Image, r image.Rectangle) error {
+	updateRect = newSDLRect(r)
+	rectPtr := unsafe.Pointer(&updateRect)
+	p := r.Min.Add(img.Bounds().Min)
+
+	var failed bool
+	switch t := img.(type) {
+	case *image.RGBA:
+		failed = sdlUpdateTexture(w.texture, rectPtr, unsafe.Pointer(&t.Pix[t.PixOffset(p.X, p.Y)]), uintptr(t.Stride))
+	case *BGRA:
+		failed = sdlUpdateTexture(w.texture, rectPtr, unsafe.Pointer(&t.Pix[t.PixOffset(p.X, p.Y)]), uintptr(t.Stride))
+	case *RGB565:
+		failed = sdlUpdateTexture(w.texture, rectPtr, unsafe.Pointer(&t.Pix[t.PixOffset(p.X, p.Y)]), uintptr(t.Stride))
+	case *NV12:
+		// SDL expects the chroma plane to follow the luma plane of the
+		// updated region, so NV12 images are always uploaded in full.
+		failed = sdlUpdateTexture(w.texture, nil, unsafe.Pointer(&t.Pix[0]), uintptr(t.Stride))
+	case *image.YCbCr:
+		y := unsafe.Pointer(&t.Y[t.YOffset(p.X, p.Y)])
+		cb := unsafe.Pointer(&t.Cb[t.COffset(p.X, p.Y)])
+		cr := unsafe.Pointer(&t.Cr[t.COffset(p.X, p.Y)])
+		failed = sdlUpdateYUVTexture(w.texture, rectPtr, y, uintptr(t.YStride), cb, uintptr(t.CStride), cr, uintptr(t.CStride))
+	default:
+		return errors.New("unsupported image type")
+	}
+
+	if failed {
+		return sdlToGoError()
+	}
+	return nil
+}
+
+// BGRA is an in-memory image with pixels stored as B, G, R, A bytes. It
+// matches PixelFormatARGB8888 and PixelFormatRGB888 on little-endian machines.
+type BGRA struct {
+	Pix    []uint8
+	Stride int
+	Rect   image.Rectangle
+}
+
+func NewBGRA(r image.Rectangle) *BGRA {
+	return &BGRA{
+		Pix:    make([]uint8, 4*r.Dx()*r.Dy()),
+		Stride: 4 * r.Dx(),
+		Rect:   r,
+	}
+}
+
+func (p *BGRA) ColorModel() color.Model {
+	return color.RGBAModel
+}
+
+func (p *BGRA) Bounds() image.Rectangle {
+	return p.Rect
+}
+
+func (p *BGRA) PixOffset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x-p.Rect.Min.X)*4
+}
+
+func (p *BGRA) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return color.RGBA{}
+	}
+	i := p.PixOffset(x, y)
+	return color.RGBA{p.Pix[i+2], p.Pix[i+1], p.Pix[i+0], p.Pix[i+3]}
+}
+
+func (p *BGRA) Set(x, y int, c color.Color) {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return
+	}
+	i := p.PixOffset(x, y)
+	v := colorToRGBA(c)
+	p.Pix[i+0], p.Pix[i+1], p.Pix[i+2], p.Pix[i+3] = v[2], v[1], v[0], v[3]
+}
+
+// RGB565 is an in-memory image with 16-bit little-endian pixels, 5 bits red,
+// 6 bits green and 5 bits blue. It matches PixelFormatRGB565.
+type RGB565 struct {
+	Pix    []uint8
+	Stride int
+	Rect   image.Rectangle
+}
+
+func NewRGB565(r image.Rectangle) *RGB565 {
+	return &RGB565{
+		Pix:    make([]uint8, 2*r.Dx()*r.Dy()),
+		Stride: 2 * r.Dx(),
+		Rect:   r,
+	}
+}
+
+func (p *RGB565) ColorModel() color.Model {
+	return color.RGBAModel
+}
+
+func (p *RGB565) Bounds() image.Rectangle {
+	return p.Rect
+}
+
+func (p *RGB565) PixOffset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x-p.Rect.Min.X)*2
+}
+
+func (p *RGB565) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return color.RGBA{}
+	}
+	i := p.PixOffset(x, y)
+	v := uint16(p.Pix[i]) | uint16(p.Pix[i+1])<<8
+
+	r, g, b := uint8(v>>11), uint8(v>>5)&0x3f, uint8(v)&0x1f
+	return color.RGBA{r<<3 | r>>2, g<<2 | g>>4, b<<3 | b>>2, 0xff}
+}
+
+func (p *RGB565) Set(x, y int, c color.Color) {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return
+	}
+	i := p.PixOffset(x, y)
+	rgba := colorToRGBA(c)
+	v := uint16(rgba[0]>>3)<<11 | uint16(rgba[1]>>2)<<5 | uint16(rgba[2]>>3)
+	p.Pix[i], p.Pix[i+1] = uint8(v), uint8(v>>8)
+}
+
+// NV12 is an in-memory image with a full resolution luma plane followed by an
+// interleaved, 4:2:0 subsampled, chroma plane using the same stride. It
+// matches PixelFormatNV12.
+type NV12 struct {
+	Pix    []uint8
+	Stride int
+	Rect   image.Rectangle
+}
+
+func NewNV12(r image.Rectangle) *NV12 {
+	w, h := r.Dx(), r.Dy()
+	stride := w + w&1
+	return &NV12{
+		Pix:    make([]uint8, stride*h+stride*((h+1)/2)),
+		Stride: stride,
+		Rect:   r,
+	}
+}
+
+func (p *NV12) ColorModel() color.Model {
+	return color.YCbCrModel
+}
+
+func (p *NV12) Bounds() image.Rectangle {
+	return p.Rect
+}
+
+func (p *NV12) YOffset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x - p.Rect.Min.X)
+}
+
+func (p *NV12) COffset(x, y int) int {
+	return p.Stride*p.Rect.Dy() + ((y-p.Rect.Min.Y)/2)*p.Stride + ((x-p.Rect.Min.X)/2)*2
+}
+
+func (p *NV12) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return color.YCbCr{}
+	}
+	ci := p.COffset(x, y)
+	return color.YCbCr{p.Pix[p.YOffset(x, y)], p.Pix[ci], p.Pix[ci+1]}
+}
+
+// Set writes the luma of c to the pixel and the chroma of c to the sample shared
+// by its 2x2 block, so the block gets the chroma of the pixel that was set last.
+func (p *NV12) Set(x, y int, c color.Color) {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return
+	}
+	ycc := color.YCbCrModel.Convert(c).(color.YCbCr)
+	ci := p.COffset(x, y)
+	p.Pix[p.YOffset(x, y)] = ycc.Y
+	p.Pix[ci], p.Pix[ci+1] = ycc.Cb, ycc.Cr
+}
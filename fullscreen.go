@@ -0,0 +1,93 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"errors"
+	"image"
+	"unsafe"
+)
+
+type FullscreenMode int
+
+const (
+	Windowed FullscreenMode = iota
+	// FullscreenDesktop covers the display with the window, without changing
+	// the display mode.
+	FullscreenDesktop
+	// FullscreenExclusive changes the display mode, see ConfigWithDisplayMode.
+	FullscreenExclusive
+)
+
+var fullscreenFlags = [...]uint32{
+	Windowed:            0,
+	FullscreenDesktop:   sdl_WINDOW_FULLSCREEN_DESKTOP,
+	FullscreenExclusive: sdl_WINDOW_FULLSCREEN,
+}
+
+// ConfigWithFullscreen opens the window in fullscreen mode.
+func ConfigWithFullscreen(mode FullscreenMode) Config {
+	return func() error {
+		if mode < Windowed || mode > FullscreenExclusive {
+			return errors.New("invalid fullscreen mode")
+		}
+		windowConfig.fullscreen = mode
+		return nil
+	}
+}
+
+func SetFullscreen(mode FullscreenMode) error {
+	if defaultWindow == nil {
+		return errWindowClosed
+	}
+	return defaultWindow.SetFullscreen(mode)
+}
+
+func IsFullscreen() (bool, error) {
+	if defaultWindow == nil {
+		return false, errWindowClosed
+	}
+	return defaultWindow.IsFullscreen()
+}
+
+// SetFullscreen changes the fullscreen mode of the window. The position and
+// size of the window are restored when it returns to windowed mode.
+func (w *Window) SetFullscreen(mode FullscreenMode) error {
+	if mode < Windowed || mode > FullscreenExclusive {
+		return errors.New("invalid fullscreen mode")
+	}
+
+	return w.command(func() error {
+		return w.setFullscreen(mode)
+	})
+}
+
+func (w *Window) IsFullscreen() (bool, error) {
+	return w.hasFlags(sdl_WINDOW_FULLSCREEN)
+}
+
+func (w *Window) setFullscreen(mode FullscreenMode) error {
+	wasFullscreen := sdlGetWindowFlags(w.window)&sdl_WINDOW_FULLSCREEN != 0
+
+	if !wasFullscreen && mode != Windowed {
+		sdlGetWindowPosition(w.window, unsafe.Pointer(&windowCoords[0]), unsafe.Pointer(&windowCoords[1]))
+		pos := image.Pt(int(windowCoords[0]), int(windowCoords[1]))
+		sdlGetWindowSize(w.window, unsafe.Pointer(&windowCoords[0]), unsafe.Pointer(&windowCoords[1]))
+		size := image.Pt(int(windowCoords[0]), int(windowCoords[1]))
+		w.windowedBounds = image.Rectangle{Min: pos, Max: pos.Add(size)}
+	}
+
+	if sdlSetWindowFullscreen(w.window, fullscreenFlags[mode]) {
+		return sdlToGoError()
+	}
+
+	// Windows that were created fullscreen have no windowed bounds, SDL then
+	// uses the size the window was created with.
+	if wasFullscreen && mode == Windowed && !w.windowedBounds.Empty() {
+		sdlSetWindowSize(w.window, w.windowedBounds.Size())
+		sdlSetWindowPosition(w.window, w.windowedBounds.Min)
+	}
+	return nil
+}
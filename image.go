@@ -0,0 +1,125 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// toRGBA returns img as an *image.RGBA. Images of other types are converted
+// into a scratch buffer that is reused between calls. Only the regions, given
+// relative to the image bounds, are converted.
+func (w *Window) toRGBA(img image.Image, regions []image.Rectangle) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+
+	b := img.Bounds()
+	size := b.Size()
+	if w.scratchBuffer == nil || w.scratchBuffer.Rect.Size() != size {
+		w.scratchBuffer = image.NewRGBA(image.Rectangle{Max: size})
+	}
+	convertToRGBA(w.scratchBuffer, img, regions)
+	return w.scratchBuffer
+}
+
+// convertToRGBA converts the regions of img, given relative to the image
+// bounds, to the same regions of dst.
+func convertToRGBA(dst *image.RGBA, img image.Image, regions []image.Rectangle) {
+	b := img.Bounds()
+
+	for _, r := range regions {
+		r = r.Add(b.Min)
+		switch src := img.(type) {
+		case *image.NRGBA:
+			convertNRGBA(dst, src, r)
+		case *image.Paletted:
+			convertPaletted(dst, src, r)
+		case *image.Gray:
+			convertGray(dst, src, r)
+		case *image.YCbCr:
+			convertYCbCr(dst, src, r)
+		default:
+			draw.Draw(dst, r.Sub(b.Min), img, r.Min, draw.Src)
+		}
+	}
+}
+
+func convertNRGBA(dst *image.RGBA, src *image.NRGBA, r image.Rectangle) {
+	w := r.Dx() * 4
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		s := src.Pix[src.PixOffset(r.Min.X, y):]
+		d := dst.Pix[dst.PixOffset(r.Min.X-src.Rect.Min.X, y-src.Rect.Min.Y):]
+
+		for i := 0; i < w; i += 4 {
+			a := uint32(s[i+3]) * 0x101
+			d[i+0] = uint8((uint32(s[i+0]) * a / 0xff) >> 8)
+			d[i+1] = uint8((uint32(s[i+1]) * a / 0xff) >> 8)
+			d[i+2] = uint8((uint32(s[i+2]) * a / 0xff) >> 8)
+			d[i+3] = s[i+3]
+		}
+	}
+}
+
+func convertPaletted(dst *image.RGBA, src *image.Paletted, r image.Rectangle) {
+	var lut [256][4]uint8
+	for i, c := range src.Palette {
+		if i >= len(lut) {
+			break
+		}
+		lut[i] = colorToRGBA(c)
+	}
+
+	w := r.Dx()
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		s := src.Pix[src.PixOffset(r.Min.X, y):]
+		d := dst.Pix[dst.PixOffset(r.Min.X-src.Rect.Min.X, y-src.Rect.Min.Y):]
+
+		for x := 0; x < w; x++ {
+			copy(d[x*4:x*4+4], lut[s[x]][:])
+		}
+	}
+}
+
+func convertGray(dst *image.RGBA, src *image.Gray, r image.Rectangle) {
+	w := r.Dx()
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		s := src.Pix[src.PixOffset(r.Min.X, y):]
+		d := dst.Pix[dst.PixOffset(r.Min.X-src.Rect.Min.X, y-src.Rect.Min.Y):]
+
+		for x := 0; x < w; x++ {
+			v := s[x]
+			d[x*4+0] = v
+			d[x*4+1] = v
+			d[x*4+2] = v
+			d[x*4+3] = 0xff
+		}
+	}
+}
+
+func convertYCbCr(dst *image.RGBA, src *image.YCbCr, r image.Rectangle) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		d := dst.Pix[dst.PixOffset(r.Min.X-src.Rect.Min.X, y-src.Rect.Min.Y):]
+
+		for x := r.Min.X; x < r.Max.X; x++ {
+			yi := src.YOffset(x, y)
+			ci := src.COffset(x, y)
+			i := (x - r.Min.X) * 4
+
+			d[i+0], d[i+1], d[i+2] = color.YCbCrToRGB(src.Y[yi], src.Cb[ci], src.Cr[ci])
+			d[i+3] = 0xff
+		}
+	}
+}
+
+func colorToRGBA(c color.Color) [4]uint8 {
+	r, g, b, a := c.RGBA()
+	return [4]uint8{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+}
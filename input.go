@@ -0,0 +1,65 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"image"
+	"unsafe"
+)
+
+const (
+	LeftButton uint8 = 1 + iota
+	MiddleButton
+	RightButton
+	X1Button
+	X2Button
+)
+
+// ButtonMask returns the bit of button b in the mask returned by MouseState
+// and in MouseMotionEvent.State.
+func ButtonMask(b uint8) uint32 {
+	return 1 << (b - 1)
+}
+
+var mousePosition [2]int32
+
+// KeyboardState returns a snapshot of the keyboard, indexed by Scancode. The
+// state is updated when events are polled with Events.
+func KeyboardState() ([]bool, error) {
+	state := make([]bool, numScancodes)
+	err := sendCommand(false, func() error {
+		for i, s := range sdlGetKeyboardState() {
+			state[i] = s != 0
+		}
+		return nil
+	})
+	return state, err
+}
+
+// ModState returns the current key modifiers, see LeftShiftMod etc.
+func ModState() (uint16, error) {
+	var mod uint16
+	err := sendCommand(false, func() error {
+		mod = sdlGetModState()
+		return nil
+	})
+	return mod, err
+}
+
+// MouseState returns the mouse position, in window coordinates, and the mask
+// of pressed buttons.
+func MouseState() (image.Point, uint32, error) {
+	var (
+		pos     image.Point
+		buttons uint32
+	)
+
+	err := sendCommand(false, func() error {
+		buttons = sdlGetMouseState(unsafe.Pointer(&mousePosition[0]), unsafe.Pointer(&mousePosition[1]))
+		pos = image.Pt(int(mousePosition[0]), int(mousePosition[1]))
+		return nil
+	})
+	return pos, buttons, err
+}
@@ -4,15 +4,251 @@
 
 package vsdl
 
-import "fmt"
+import "unicode/utf8"
+
+const scancodeMask = 1 << 30
 
 const (
-	ReturnKey    Keycode = '\r'
-	EscKey       Keycode = '\033'
-	BackSpaceKey Keycode = '\b'
-	TabKey       Keycode = '\t'
-	SpaceKey     Keycode = ' '
-	DeleteKey    Keycode = '\177'
+	UnknownKey              Keycode = 0
+	AKey                    Keycode = 'a'
+	BKey                    Keycode = 'b'
+	CKey                    Keycode = 'c'
+	DKey                    Keycode = 'd'
+	EKey                    Keycode = 'e'
+	FKey                    Keycode = 'f'
+	GKey                    Keycode = 'g'
+	HKey                    Keycode = 'h'
+	IKey                    Keycode = 'i'
+	JKey                    Keycode = 'j'
+	KKey                    Keycode = 'k'
+	LKey                    Keycode = 'l'
+	MKey                    Keycode = 'm'
+	NKey                    Keycode = 'n'
+	OKey                    Keycode = 'o'
+	PKey                    Keycode = 'p'
+	QKey                    Keycode = 'q'
+	RKey                    Keycode = 'r'
+	SKey                    Keycode = 's'
+	TKey                    Keycode = 't'
+	UKey                    Keycode = 'u'
+	VKey                    Keycode = 'v'
+	WKey                    Keycode = 'w'
+	XKey                    Keycode = 'x'
+	YKey                    Keycode = 'y'
+	ZKey                    Keycode = 'z'
+	Num1Key                 Keycode = '1'
+	Num2Key                 Keycode = '2'
+	Num3Key                 Keycode = '3'
+	Num4Key                 Keycode = '4'
+	Num5Key                 Keycode = '5'
+	Num6Key                 Keycode = '6'
+	Num7Key                 Keycode = '7'
+	Num8Key                 Keycode = '8'
+	Num9Key                 Keycode = '9'
+	Num0Key                 Keycode = '0'
+	ReturnKey               Keycode = '\r'
+	EscKey                  Keycode = '\033'
+	BackSpaceKey            Keycode = '\b'
+	TabKey                  Keycode = '\t'
+	SpaceKey                Keycode = ' '
+	MinusKey                Keycode = '-'
+	EqualsKey               Keycode = '='
+	LeftBracketKey          Keycode = '['
+	RightBracketKey         Keycode = ']'
+	BackslashKey            Keycode = '\\'
+	SemicolonKey            Keycode = ';'
+	QuoteKey                Keycode = '\''
+	BackQuoteKey            Keycode = '`'
+	CommaKey                Keycode = ','
+	PeriodKey               Keycode = '.'
+	SlashKey                Keycode = '/'
+	CapsLockKey                     = Keycode(CapsLockScancode | scancodeMask)
+	F1Key                           = Keycode(F1Scancode | scancodeMask)
+	F2Key                           = Keycode(F2Scancode | scancodeMask)
+	F3Key                           = Keycode(F3Scancode | scancodeMask)
+	F4Key                           = Keycode(F4Scancode | scancodeMask)
+	F5Key                           = Keycode(F5Scancode | scancodeMask)
+	F6Key                           = Keycode(F6Scancode | scancodeMask)
+	F7Key                           = Keycode(F7Scancode | scancodeMask)
+	F8Key                           = Keycode(F8Scancode | scancodeMask)
+	F9Key                           = Keycode(F9Scancode | scancodeMask)
+	F10Key                          = Keycode(F10Scancode | scancodeMask)
+	F11Key                          = Keycode(F11Scancode | scancodeMask)
+	F12Key                          = Keycode(F12Scancode | scancodeMask)
+	PrintScreenKey                  = Keycode(PrintScreenScancode | scancodeMask)
+	ScrollLockKey                   = Keycode(ScrollLockScancode | scancodeMask)
+	PauseKey                        = Keycode(PauseScancode | scancodeMask)
+	InsertKey                       = Keycode(InsertScancode | scancodeMask)
+	HomeKey                         = Keycode(HomeScancode | scancodeMask)
+	PageUpKey                       = Keycode(PageUpScancode | scancodeMask)
+	DeleteKey               Keycode = '\177'
+	EndKey                          = Keycode(EndScancode | scancodeMask)
+	PageDownKey                     = Keycode(PageDownScancode | scancodeMask)
+	RightKey                        = Keycode(RightScancode | scancodeMask)
+	LeftKey                         = Keycode(LeftScancode | scancodeMask)
+	DownKey                         = Keycode(DownScancode | scancodeMask)
+	UpKey                           = Keycode(UpScancode | scancodeMask)
+	NumLockClearKey                 = Keycode(NumLockClearScancode | scancodeMask)
+	KeypadDivideKey                 = Keycode(KeypadDivideScancode | scancodeMask)
+	KeypadMultiplyKey               = Keycode(KeypadMultiplyScancode | scancodeMask)
+	KeypadMinusKey                  = Keycode(KeypadMinusScancode | scancodeMask)
+	KeypadPlusKey                   = Keycode(KeypadPlusScancode | scancodeMask)
+	KeypadEnterKey                  = Keycode(KeypadEnterScancode | scancodeMask)
+	Keypad1Key                      = Keycode(Keypad1Scancode | scancodeMask)
+	Keypad2Key                      = Keycode(Keypad2Scancode | scancodeMask)
+	Keypad3Key                      = Keycode(Keypad3Scancode | scancodeMask)
+	Keypad4Key                      = Keycode(Keypad4Scancode | scancodeMask)
+	Keypad5Key                      = Keycode(Keypad5Scancode | scancodeMask)
+	Keypad6Key                      = Keycode(Keypad6Scancode | scancodeMask)
+	Keypad7Key                      = Keycode(Keypad7Scancode | scancodeMask)
+	Keypad8Key                      = Keycode(Keypad8Scancode | scancodeMask)
+	Keypad9Key                      = Keycode(Keypad9Scancode | scancodeMask)
+	Keypad0Key                      = Keycode(Keypad0Scancode | scancodeMask)
+	KeypadPeriodKey                 = Keycode(KeypadPeriodScancode | scancodeMask)
+	ApplicationKey                  = Keycode(ApplicationScancode | scancodeMask)
+	PowerKey                        = Keycode(PowerScancode | scancodeMask)
+	KeypadEqualsKey                 = Keycode(KeypadEqualsScancode | scancodeMask)
+	F13Key                          = Keycode(F13Scancode | scancodeMask)
+	F14Key                          = Keycode(F14Scancode | scancodeMask)
+	F15Key                          = Keycode(F15Scancode | scancodeMask)
+	F16Key                          = Keycode(F16Scancode | scancodeMask)
+	F17Key                          = Keycode(F17Scancode | scancodeMask)
+	F18Key                          = Keycode(F18Scancode | scancodeMask)
+	F19Key                          = Keycode(F19Scancode | scancodeMask)
+	F20Key                          = Keycode(F20Scancode | scancodeMask)
+	F21Key                          = Keycode(F21Scancode | scancodeMask)
+	F22Key                          = Keycode(F22Scancode | scancodeMask)
+	F23Key                          = Keycode(F23Scancode | scancodeMask)
+	F24Key                          = Keycode(F24Scancode | scancodeMask)
+	ExecuteKey                      = Keycode(ExecuteScancode | scancodeMask)
+	HelpKey                         = Keycode(HelpScancode | scancodeMask)
+	MenuKey                         = Keycode(MenuScancode | scancodeMask)
+	SelectKey                       = Keycode(SelectScancode | scancodeMask)
+	StopKey                         = Keycode(StopScancode | scancodeMask)
+	AgainKey                        = Keycode(AgainScancode | scancodeMask)
+	UndoKey                         = Keycode(UndoScancode | scancodeMask)
+	CutKey                          = Keycode(CutScancode | scancodeMask)
+	CopyKey                         = Keycode(CopyScancode | scancodeMask)
+	PasteKey                        = Keycode(PasteScancode | scancodeMask)
+	FindKey                         = Keycode(FindScancode | scancodeMask)
+	MuteKey                         = Keycode(MuteScancode | scancodeMask)
+	VolumeUpKey                     = Keycode(VolumeUpScancode | scancodeMask)
+	VolumeDownKey                   = Keycode(VolumeDownScancode | scancodeMask)
+	KeypadCommaKey                  = Keycode(KeypadCommaScancode | scancodeMask)
+	KeypadEqualsAS400Key            = Keycode(KeypadEqualsAS400Scancode | scancodeMask)
+	AltEraseKey                     = Keycode(AltEraseScancode | scancodeMask)
+	SysReqKey                       = Keycode(SysReqScancode | scancodeMask)
+	CancelKey                       = Keycode(CancelScancode | scancodeMask)
+	ClearKey                        = Keycode(ClearScancode | scancodeMask)
+	PriorKey                        = Keycode(PriorScancode | scancodeMask)
+	Return2Key                      = Keycode(Return2Scancode | scancodeMask)
+	SeparatorKey                    = Keycode(SeparatorScancode | scancodeMask)
+	OutKey                          = Keycode(OutScancode | scancodeMask)
+	OperKey                         = Keycode(OperScancode | scancodeMask)
+	ClearAgainKey                   = Keycode(ClearAgainScancode | scancodeMask)
+	CrSelKey                        = Keycode(CrSelScancode | scancodeMask)
+	ExSelKey                        = Keycode(ExSelScancode | scancodeMask)
+	Keypad00Key                     = Keycode(Keypad00Scancode | scancodeMask)
+	Keypad000Key                    = Keycode(Keypad000Scancode | scancodeMask)
+	ThousandsSeparatorKey           = Keycode(ThousandsSeparatorScancode | scancodeMask)
+	DecimalSeparatorKey             = Keycode(DecimalSeparatorScancode | scancodeMask)
+	CurrencyUnitKey                 = Keycode(CurrencyUnitScancode | scancodeMask)
+	CurrencySubUnitKey              = Keycode(CurrencySubUnitScancode | scancodeMask)
+	KeypadLeftParenKey              = Keycode(KeypadLeftParenScancode | scancodeMask)
+	KeypadRightParenKey             = Keycode(KeypadRightParenScancode | scancodeMask)
+	KeypadLeftBraceKey              = Keycode(KeypadLeftBraceScancode | scancodeMask)
+	KeypadRightBraceKey             = Keycode(KeypadRightBraceScancode | scancodeMask)
+	KeypadTabKey                    = Keycode(KeypadTabScancode | scancodeMask)
+	KeypadBackSpaceKey              = Keycode(KeypadBackSpaceScancode | scancodeMask)
+	KeypadAKey                      = Keycode(KeypadAScancode | scancodeMask)
+	KeypadBKey                      = Keycode(KeypadBScancode | scancodeMask)
+	KeypadCKey                      = Keycode(KeypadCScancode | scancodeMask)
+	KeypadDKey                      = Keycode(KeypadDScancode | scancodeMask)
+	KeypadEKey                      = Keycode(KeypadEScancode | scancodeMask)
+	KeypadFKey                      = Keycode(KeypadFScancode | scancodeMask)
+	KeypadXorKey                    = Keycode(KeypadXorScancode | scancodeMask)
+	KeypadPowerKey                  = Keycode(KeypadPowerScancode | scancodeMask)
+	KeypadPercentKey                = Keycode(KeypadPercentScancode | scancodeMask)
+	KeypadLessKey                   = Keycode(KeypadLessScancode | scancodeMask)
+	KeypadGreaterKey                = Keycode(KeypadGreaterScancode | scancodeMask)
+	KeypadAmpersandKey              = Keycode(KeypadAmpersandScancode | scancodeMask)
+	KeypadDblAmpersandKey           = Keycode(KeypadDblAmpersandScancode | scancodeMask)
+	KeypadVerticalBarKey            = Keycode(KeypadVerticalBarScancode | scancodeMask)
+	KeypadDblVerticalBarKey         = Keycode(KeypadDblVerticalBarScancode | scancodeMask)
+	KeypadColonKey                  = Keycode(KeypadColonScancode | scancodeMask)
+	KeypadHashKey                   = Keycode(KeypadHashScancode | scancodeMask)
+	KeypadSpaceKey                  = Keycode(KeypadSpaceScancode | scancodeMask)
+	KeypadAtKey                     = Keycode(KeypadAtScancode | scancodeMask)
+	KeypadExclaimKey                = Keycode(KeypadExclaimScancode | scancodeMask)
+	KeypadMemStoreKey               = Keycode(KeypadMemStoreScancode | scancodeMask)
+	KeypadMemRecallKey              = Keycode(KeypadMemRecallScancode | scancodeMask)
+	KeypadMemClearKey               = Keycode(KeypadMemClearScancode | scancodeMask)
+	KeypadMemAddKey                 = Keycode(KeypadMemAddScancode | scancodeMask)
+	KeypadMemSubtractKey            = Keycode(KeypadMemSubtractScancode | scancodeMask)
+	KeypadMemMultiplyKey            = Keycode(KeypadMemMultiplyScancode | scancodeMask)
+	KeypadMemDivideKey              = Keycode(KeypadMemDivideScancode | scancodeMask)
+	KeypadPlusMinusKey              = Keycode(KeypadPlusMinusScancode | scancodeMask)
+	KeypadClearKey                  = Keycode(KeypadClearScancode | scancodeMask)
+	KeypadClearEntryKey             = Keycode(KeypadClearEntryScancode | scancodeMask)
+	KeypadBinaryKey                 = Keycode(KeypadBinaryScancode | scancodeMask)
+	KeypadOctalKey                  = Keycode(KeypadOctalScancode | scancodeMask)
+	KeypadDecimalKey                = Keycode(KeypadDecimalScancode | scancodeMask)
+	KeypadHexadecimalKey            = Keycode(KeypadHexadecimalScancode | scancodeMask)
+	LeftCtrlKey                     = Keycode(LeftCtrlScancode | scancodeMask)
+	LeftShiftKey                    = Keycode(LeftShiftScancode | scancodeMask)
+	LeftAltKey                      = Keycode(LeftAltScancode | scancodeMask)
+	LeftGuiKey                      = Keycode(LeftGuiScancode | scancodeMask)
+	RightCtrlKey                    = Keycode(RightCtrlScancode | scancodeMask)
+	RightShiftKey                   = Keycode(RightShiftScancode | scancodeMask)
+	RightAltKey                     = Keycode(RightAltScancode | scancodeMask)
+	RightGuiKey                     = Keycode(RightGuiScancode | scancodeMask)
+	ModeKey                         = Keycode(ModeScancode | scancodeMask)
+	AudioNextKey                    = Keycode(AudioNextScancode | scancodeMask)
+	AudioPrevKey                    = Keycode(AudioPrevScancode | scancodeMask)
+	AudioStopKey                    = Keycode(AudioStopScancode | scancodeMask)
+	AudioPlayKey                    = Keycode(AudioPlayScancode | scancodeMask)
+	AudioMuteKey                    = Keycode(AudioMuteScancode | scancodeMask)
+	MediaSelectKey                  = Keycode(MediaSelectScancode | scancodeMask)
+	WWWKey                          = Keycode(WWWScancode | scancodeMask)
+	MailKey                         = Keycode(MailScancode | scancodeMask)
+	CalculatorKey                   = Keycode(CalculatorScancode | scancodeMask)
+	ComputerKey                     = Keycode(ComputerScancode | scancodeMask)
+	ACSearchKey                     = Keycode(ACSearchScancode | scancodeMask)
+	ACHomeKey                       = Keycode(ACHomeScancode | scancodeMask)
+	ACBackKey                       = Keycode(ACBackScancode | scancodeMask)
+	ACForwardKey                    = Keycode(ACForwardScancode | scancodeMask)
+	ACStopKey                       = Keycode(ACStopScancode | scancodeMask)
+	ACRefreshKey                    = Keycode(ACRefreshScancode | scancodeMask)
+	ACBookmarksKey                  = Keycode(ACBookmarksScancode | scancodeMask)
+	BrightnessDownKey               = Keycode(BrightnessDownScancode | scancodeMask)
+	BrightnessUpKey                 = Keycode(BrightnessUpScancode | scancodeMask)
+	DisplaySwitchKey                = Keycode(DisplaySwitchScancode | scancodeMask)
+	KbdIllumToggleKey               = Keycode(KbdIllumToggleScancode | scancodeMask)
+	KbdIllumDownKey                 = Keycode(KbdIllumDownScancode | scancodeMask)
+	KbdIllumUpKey                   = Keycode(KbdIllumUpScancode | scancodeMask)
+	EjectKey                        = Keycode(EjectScancode | scancodeMask)
+	SleepKey                        = Keycode(SleepScancode | scancodeMask)
+	App1Key                         = Keycode(App1Scancode | scancodeMask)
+	App2Key                         = Keycode(App2Scancode | scancodeMask)
+	AudioRewindKey                  = Keycode(AudioRewindScancode | scancodeMask)
+	AudioFastForwardKey             = Keycode(AudioFastForwardScancode | scancodeMask)
+	ExclaimKey              Keycode = '!'
+	QuoteDblKey             Keycode = '"'
+	HashKey                 Keycode = '#'
+	PercentKey              Keycode = '%'
+	DollarKey               Keycode = '$'
+	AmpersandKey            Keycode = '&'
+	LeftParenKey            Keycode = '('
+	RightParenKey           Keycode = ')'
+	AsteriskKey             Keycode = '*'
+	PlusKey                 Keycode = '+'
+	ColonKey                Keycode = ':'
+	LessKey                 Keycode = '<'
+	GreaterKey              Keycode = '>'
+	QuestionKey             Keycode = '?'
+	AtKey                   Keycode = '@'
+	CaretKey                Keycode = '^'
+	UnderscoreKey           Keycode = '_'
 )
 
 const (
@@ -40,16 +276,307 @@ const (
 // Keycode (https://wiki.libsdl.org/SDL_Keycode)
 type Keycode int32
 
+var defaultKeymap = [numScancodes]Keycode{
+	AScancode:                    AKey,
+	BScancode:                    BKey,
+	CScancode:                    CKey,
+	DScancode:                    DKey,
+	EScancode:                    EKey,
+	FScancode:                    FKey,
+	GScancode:                    GKey,
+	HScancode:                    HKey,
+	IScancode:                    IKey,
+	JScancode:                    JKey,
+	KScancode:                    KKey,
+	LScancode:                    LKey,
+	MScancode:                    MKey,
+	NScancode:                    NKey,
+	OScancode:                    OKey,
+	PScancode:                    PKey,
+	QScancode:                    QKey,
+	RScancode:                    RKey,
+	SScancode:                    SKey,
+	TScancode:                    TKey,
+	UScancode:                    UKey,
+	VScancode:                    VKey,
+	WScancode:                    WKey,
+	XScancode:                    XKey,
+	YScancode:                    YKey,
+	ZScancode:                    ZKey,
+	Num1Scancode:                 Num1Key,
+	Num2Scancode:                 Num2Key,
+	Num3Scancode:                 Num3Key,
+	Num4Scancode:                 Num4Key,
+	Num5Scancode:                 Num5Key,
+	Num6Scancode:                 Num6Key,
+	Num7Scancode:                 Num7Key,
+	Num8Scancode:                 Num8Key,
+	Num9Scancode:                 Num9Key,
+	Num0Scancode:                 Num0Key,
+	ReturnScancode:               ReturnKey,
+	EscScancode:                  EscKey,
+	BackSpaceScancode:            BackSpaceKey,
+	TabScancode:                  TabKey,
+	SpaceScancode:                SpaceKey,
+	MinusScancode:                MinusKey,
+	EqualsScancode:               EqualsKey,
+	LeftBracketScancode:          LeftBracketKey,
+	RightBracketScancode:         RightBracketKey,
+	BackslashScancode:            BackslashKey,
+	SemicolonScancode:            SemicolonKey,
+	ApostropheScancode:           QuoteKey,
+	GraveScancode:                BackQuoteKey,
+	CommaScancode:                CommaKey,
+	PeriodScancode:               PeriodKey,
+	SlashScancode:                SlashKey,
+	CapsLockScancode:             CapsLockKey,
+	F1Scancode:                   F1Key,
+	F2Scancode:                   F2Key,
+	F3Scancode:                   F3Key,
+	F4Scancode:                   F4Key,
+	F5Scancode:                   F5Key,
+	F6Scancode:                   F6Key,
+	F7Scancode:                   F7Key,
+	F8Scancode:                   F8Key,
+	F9Scancode:                   F9Key,
+	F10Scancode:                  F10Key,
+	F11Scancode:                  F11Key,
+	F12Scancode:                  F12Key,
+	PrintScreenScancode:          PrintScreenKey,
+	ScrollLockScancode:           ScrollLockKey,
+	PauseScancode:                PauseKey,
+	InsertScancode:               InsertKey,
+	HomeScancode:                 HomeKey,
+	PageUpScancode:               PageUpKey,
+	DeleteScancode:               DeleteKey,
+	EndScancode:                  EndKey,
+	PageDownScancode:             PageDownKey,
+	RightScancode:                RightKey,
+	LeftScancode:                 LeftKey,
+	DownScancode:                 DownKey,
+	UpScancode:                   UpKey,
+	NumLockClearScancode:         NumLockClearKey,
+	KeypadDivideScancode:         KeypadDivideKey,
+	KeypadMultiplyScancode:       KeypadMultiplyKey,
+	KeypadMinusScancode:          KeypadMinusKey,
+	KeypadPlusScancode:           KeypadPlusKey,
+	KeypadEnterScancode:          KeypadEnterKey,
+	Keypad1Scancode:              Keypad1Key,
+	Keypad2Scancode:              Keypad2Key,
+	Keypad3Scancode:              Keypad3Key,
+	Keypad4Scancode:              Keypad4Key,
+	Keypad5Scancode:              Keypad5Key,
+	Keypad6Scancode:              Keypad6Key,
+	Keypad7Scancode:              Keypad7Key,
+	Keypad8Scancode:              Keypad8Key,
+	Keypad9Scancode:              Keypad9Key,
+	Keypad0Scancode:              Keypad0Key,
+	KeypadPeriodScancode:         KeypadPeriodKey,
+	ApplicationScancode:          ApplicationKey,
+	PowerScancode:                PowerKey,
+	KeypadEqualsScancode:         KeypadEqualsKey,
+	F13Scancode:                  F13Key,
+	F14Scancode:                  F14Key,
+	F15Scancode:                  F15Key,
+	F16Scancode:                  F16Key,
+	F17Scancode:                  F17Key,
+	F18Scancode:                  F18Key,
+	F19Scancode:                  F19Key,
+	F20Scancode:                  F20Key,
+	F21Scancode:                  F21Key,
+	F22Scancode:                  F22Key,
+	F23Scancode:                  F23Key,
+	F24Scancode:                  F24Key,
+	ExecuteScancode:              ExecuteKey,
+	HelpScancode:                 HelpKey,
+	MenuScancode:                 MenuKey,
+	SelectScancode:               SelectKey,
+	StopScancode:                 StopKey,
+	AgainScancode:                AgainKey,
+	UndoScancode:                 UndoKey,
+	CutScancode:                  CutKey,
+	CopyScancode:                 CopyKey,
+	PasteScancode:                PasteKey,
+	FindScancode:                 FindKey,
+	MuteScancode:                 MuteKey,
+	VolumeUpScancode:             VolumeUpKey,
+	VolumeDownScancode:           VolumeDownKey,
+	KeypadCommaScancode:          KeypadCommaKey,
+	KeypadEqualsAS400Scancode:    KeypadEqualsAS400Key,
+	AltEraseScancode:             AltEraseKey,
+	SysReqScancode:               SysReqKey,
+	CancelScancode:               CancelKey,
+	ClearScancode:                ClearKey,
+	PriorScancode:                PriorKey,
+	Return2Scancode:              Return2Key,
+	SeparatorScancode:            SeparatorKey,
+	OutScancode:                  OutKey,
+	OperScancode:                 OperKey,
+	ClearAgainScancode:           ClearAgainKey,
+	CrSelScancode:                CrSelKey,
+	ExSelScancode:                ExSelKey,
+	Keypad00Scancode:             Keypad00Key,
+	Keypad000Scancode:            Keypad000Key,
+	ThousandsSeparatorScancode:   ThousandsSeparatorKey,
+	DecimalSeparatorScancode:     DecimalSeparatorKey,
+	CurrencyUnitScancode:         CurrencyUnitKey,
+	CurrencySubUnitScancode:      CurrencySubUnitKey,
+	KeypadLeftParenScancode:      KeypadLeftParenKey,
+	KeypadRightParenScancode:     KeypadRightParenKey,
+	KeypadLeftBraceScancode:      KeypadLeftBraceKey,
+	KeypadRightBraceScancode:     KeypadRightBraceKey,
+	KeypadTabScancode:            KeypadTabKey,
+	KeypadBackSpaceScancode:      KeypadBackSpaceKey,
+	KeypadAScancode:              KeypadAKey,
+	KeypadBScancode:              KeypadBKey,
+	KeypadCScancode:              KeypadCKey,
+	KeypadDScancode:              KeypadDKey,
+	KeypadEScancode:              KeypadEKey,
+	KeypadFScancode:              KeypadFKey,
+	KeypadXorScancode:            KeypadXorKey,
+	KeypadPowerScancode:          KeypadPowerKey,
+	KeypadPercentScancode:        KeypadPercentKey,
+	KeypadLessScancode:           KeypadLessKey,
+	KeypadGreaterScancode:        KeypadGreaterKey,
+	KeypadAmpersandScancode:      KeypadAmpersandKey,
+	KeypadDblAmpersandScancode:   KeypadDblAmpersandKey,
+	KeypadVerticalBarScancode:    KeypadVerticalBarKey,
+	KeypadDblVerticalBarScancode: KeypadDblVerticalBarKey,
+	KeypadColonScancode:          KeypadColonKey,
+	KeypadHashScancode:           KeypadHashKey,
+	KeypadSpaceScancode:          KeypadSpaceKey,
+	KeypadAtScancode:             KeypadAtKey,
+	KeypadExclaimScancode:        KeypadExclaimKey,
+	KeypadMemStoreScancode:       KeypadMemStoreKey,
+	KeypadMemRecallScancode:      KeypadMemRecallKey,
+	KeypadMemClearScancode:       KeypadMemClearKey,
+	KeypadMemAddScancode:         KeypadMemAddKey,
+	KeypadMemSubtractScancode:    KeypadMemSubtractKey,
+	KeypadMemMultiplyScancode:    KeypadMemMultiplyKey,
+	KeypadMemDivideScancode:      KeypadMemDivideKey,
+	KeypadPlusMinusScancode:      KeypadPlusMinusKey,
+	KeypadClearScancode:          KeypadClearKey,
+	KeypadClearEntryScancode:     KeypadClearEntryKey,
+	KeypadBinaryScancode:         KeypadBinaryKey,
+	KeypadOctalScancode:          KeypadOctalKey,
+	KeypadDecimalScancode:        KeypadDecimalKey,
+	KeypadHexadecimalScancode:    KeypadHexadecimalKey,
+	LeftCtrlScancode:             LeftCtrlKey,
+	LeftShiftScancode:            LeftShiftKey,
+	LeftAltScancode:              LeftAltKey,
+	LeftGuiScancode:              LeftGuiKey,
+	RightCtrlScancode:            RightCtrlKey,
+	RightShiftScancode:           RightShiftKey,
+	RightAltScancode:             RightAltKey,
+	RightGuiScancode:             RightGuiKey,
+	ModeScancode:                 ModeKey,
+	AudioNextScancode:            AudioNextKey,
+	AudioPrevScancode:            AudioPrevKey,
+	AudioStopScancode:            AudioStopKey,
+	AudioPlayScancode:            AudioPlayKey,
+	AudioMuteScancode:            AudioMuteKey,
+	MediaSelectScancode:          MediaSelectKey,
+	WWWScancode:                  WWWKey,
+	MailScancode:                 MailKey,
+	CalculatorScancode:           CalculatorKey,
+	ComputerScancode:             ComputerKey,
+	ACSearchScancode:             ACSearchKey,
+	ACHomeScancode:               ACHomeKey,
+	ACBackScancode:               ACBackKey,
+	ACForwardScancode:            ACForwardKey,
+	ACStopScancode:               ACStopKey,
+	ACRefreshScancode:            ACRefreshKey,
+	ACBookmarksScancode:          ACBookmarksKey,
+	BrightnessDownScancode:       BrightnessDownKey,
+	BrightnessUpScancode:         BrightnessUpKey,
+	DisplaySwitchScancode:        DisplaySwitchKey,
+	KbdIllumToggleScancode:       KbdIllumToggleKey,
+	KbdIllumDownScancode:         KbdIllumDownKey,
+	KbdIllumUpScancode:           KbdIllumUpKey,
+	EjectScancode:                EjectKey,
+	SleepScancode:                SleepKey,
+	App1Scancode:                 App1Key,
+	App2Scancode:                 App2Key,
+	AudioRewindScancode:          AudioRewindKey,
+	AudioFastForwardScancode:     AudioFastForwardKey,
+}
+
+// Name returns the same name as SDL_GetKeyName, keys that produce a
+// character are named by the upper case character.
+func (k Keycode) Name() string {
+	if k&scancodeMask != 0 {
+		return Scancode(k &^ scancodeMask).Name()
+	}
+
+	switch k {
+	case UnknownKey:
+		return ""
+	case ReturnKey:
+		return ReturnScancode.Name()
+	case EscKey:
+		return EscScancode.Name()
+	case BackSpaceKey:
+		return BackSpaceScancode.Name()
+	case TabKey:
+		return TabScancode.Name()
+	case SpaceKey:
+		return SpaceScancode.Name()
+	case DeleteKey:
+		return DeleteScancode.Name()
+	}
+
+	if k >= 'a' && k <= 'z' {
+		k -= 'a' - 'A'
+	}
+	if !utf8.ValidRune(rune(k)) {
+		return ""
+	}
+	return string(rune(k))
+}
+
+// KeycodeFromName is the inverse of Keycode.Name, it works like
+// SDL_GetKeyFromName.
+func KeycodeFromName(name string) Keycode {
+	if name == "" {
+		return UnknownKey
+	}
+
+	if r, n := utf8.DecodeRuneInString(name); r != utf8.RuneError && (r >= utf8.RuneSelf || n == len(name)) {
+		if r >= 'A' && r <= 'Z' {
+			r += 'a' - 'A'
+		}
+		return Keycode(r)
+	}
+	return defaultKeymap[ScancodeFromName(name)]
+}
+
+// KeycodeFromScancode returns the key code that the scancode produces with
+// the current keyboard layout.
+func KeycodeFromScancode(s Scancode) (Keycode, error) {
+	var k Keycode
+	err := sendCommand(false, func() error {
+		k = sdlGetKeyFromScancode(s)
+		return nil
+	})
+	return k, err
+}
+
 // Keysym (https://wiki.libsdl.org/SDL_Keysym)
 type Keysym struct {
-	_   uint32
-	Sym Keycode
-	Mod uint16
-	_   uint32
+	Scancode Scancode
+	Sym      Keycode
+	Mod      uint16
+	_        uint32
 }
 
+// String returns the name of the key. It does not take modifiers or the input
+// method into account, use TextInputEvent to read text.
 func (ks Keysym) String() string {
-	return fmt.Sprintf("%c", ks.Sym)
+	return ks.Sym.Name()
+}
+
+func (ks Keysym) IsScancode(s Scancode) bool {
+	return ks.Scancode == s
 }
 
 func (ks Keysym) IsKey(k Keycode) bool {
@@ -0,0 +1,87 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import "testing"
+
+// Like SDL, a few keys share a name and the name lookup returns the first of
+// them. The other keys round-trip through their name.
+func TestKeycodeNameRoundTrip(t *testing.T) {
+	first := map[string]Keycode{}
+	for s, k := range defaultKeymap {
+		if k == UnknownKey {
+			continue
+		}
+
+		name := k.Name()
+		if name == "" {
+			t.Errorf("key code %#x of scancode %d has no name", uint32(k), s)
+			continue
+		}
+		if _, ok := first[name]; !ok {
+			first[name] = k
+		}
+
+		if got := KeycodeFromName(name); got != first[name] {
+			t.Errorf("KeycodeFromName(%q) is %#x, expected %#x", name, uint32(got), uint32(first[name]))
+		}
+	}
+}
+
+func TestScancodeNameRoundTrip(t *testing.T) {
+	first := map[string]Scancode{}
+	for s, name := range scancodeNames {
+		if name == "" {
+			continue
+		}
+		if _, ok := first[name]; !ok {
+			first[name] = Scancode(s)
+		}
+
+		if got := ScancodeFromName(name); got != first[name] {
+			t.Errorf("ScancodeFromName(%q) is %d, expected %d", name, got, first[name])
+		}
+	}
+}
+
+func TestKeycodeSDLNames(t *testing.T) {
+	tests := []struct {
+		key  Keycode
+		name string
+	}{
+		{AKey, "A"},
+		{Num1Key, "1"},
+		{ReturnKey, "Return"},
+		{EscKey, "Escape"},
+		{BackSpaceKey, "Backspace"},
+		{TabKey, "Tab"},
+		{SpaceKey, "Space"},
+		{DeleteKey, "Delete"},
+		{CapsLockKey, "CapsLock"},
+		{F24Key, "F24"},
+		{PageUpKey, "PageUp"},
+		{UpKey, "Up"},
+		{KeypadEnterKey, "Keypad Enter"},
+		{Keypad5Key, "Keypad 5"},
+		{LeftShiftKey, "Left Shift"},
+		{RightAltKey, "Right Alt"},
+		{LeftGuiKey, "Left GUI"},
+		{AudioPlayKey, "AudioPlay"},
+		{UnknownKey, ""},
+	}
+
+	for _, tt := range tests {
+		if name := tt.key.Name(); name != tt.name {
+			t.Errorf("name of %#x is %q, expected %q", uint32(tt.key), name, tt.name)
+		}
+		if k := KeycodeFromName(tt.name); k != tt.key {
+			t.Errorf("KeycodeFromName(%q) is %#x, expected %#x", tt.name, uint32(k), uint32(tt.key))
+		}
+	}
+
+	if k := KeycodeFromName("left shift"); k != LeftShiftKey {
+		t.Errorf("KeycodeFromName is not case insensitive")
+	}
+}
@@ -0,0 +1,259 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"unsafe"
+)
+
+// Mixer plays sounds on an audio device. Sounds are resampled to the device
+// rate with linear interpolation.
+type Mixer struct {
+	lock   sync.Mutex
+	device *AudioDevice
+	spec   AudioSpec
+	volume float32
+	voices []*Voice
+	accum  []float32
+}
+
+// Voice is a playing sound.
+type Voice struct {
+	mixer  *Mixer
+	sound  *Sound
+	pos    float64
+	step   float64
+	volume float32
+	pan    float32
+	loop   bool
+	done   bool
+}
+
+// NewMixer opens the audio output device with name, an empty name selects the
+// default device, and starts playback.
+func NewMixer(name string, spec AudioSpec) (*Mixer, error) {
+	m := &Mixer{volume: 1}
+
+	d, err := OpenAudioDevice(name, spec, m.Mix)
+	if err != nil {
+		return nil, err
+	}
+
+	m.lock.Lock()
+	m.device = d
+	m.spec = d.Spec()
+	m.lock.Unlock()
+
+	if err := d.Resume(); err != nil {
+		d.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// NewMixerWithSpec creates a mixer that is not attached to a device, Mix must
+// be called to produce samples in the format of spec.
+func NewMixerWithSpec(spec AudioSpec) *Mixer {
+	spec.setDefaults()
+	return &Mixer{volume: 1, spec: spec}
+}
+
+// Close stops all voices and closes the audio device, if the mixer has one.
+func (m *Mixer) Close() error {
+	m.lock.Lock()
+	for _, v := range m.voices {
+		v.done = true
+	}
+	m.voices = nil
+	d := m.device
+	m.device = nil
+	m.lock.Unlock()
+
+	if d != nil {
+		return d.Close()
+	}
+	return nil
+}
+
+// SetVolume sets the master volume, 1 is unchanged.
+func (m *Mixer) SetVolume(v float32) {
+	m.lock.Lock()
+	m.volume = v
+	m.lock.Unlock()
+}
+
+// Play starts playing s with full volume and centered pan.
+func (m *Mixer) Play(s *Sound) (*Voice, error) {
+	if s.Channels <= 0 || s.Freq <= 0 {
+		return nil, errors.New("invalid sound")
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	v := &Voice{
+		mixer:  m,
+		sound:  s,
+		step:   float64(s.Freq) / float64(m.spec.Freq),
+		volume: 1,
+	}
+	m.voices = append(m.voices, v)
+	return v, nil
+}
+
+// SetVolume sets the volume of the voice, 1 is unchanged.
+func (v *Voice) SetVolume(vol float32) {
+	v.mixer.lock.Lock()
+	v.volume = vol
+	v.mixer.lock.Unlock()
+}
+
+// SetPan sets the stereo balance from -1, left, to 1, right.
+func (v *Voice) SetPan(pan float32) {
+	if pan < -1 {
+		pan = -1
+	} else if pan > 1 {
+		pan = 1
+	}
+
+	v.mixer.lock.Lock()
+	v.pan = pan
+	v.mixer.lock.Unlock()
+}
+
+// SetLoop makes the voice restart from the beginning when it ends.
+func (v *Voice) SetLoop(b bool) {
+	v.mixer.lock.Lock()
+	v.loop = b
+	v.mixer.lock.Unlock()
+}
+
+func (v *Voice) Stop() {
+	v.mixer.lock.Lock()
+	v.done = true
+	v.mixer.lock.Unlock()
+}
+
+func (v *Voice) Playing() bool {
+	v.mixer.lock.Lock()
+	defer v.mixer.lock.Unlock()
+	return !v.done
+}
+
+// Mix fills buf with the mixed voices, it has the signature of an
+// AudioCallback.
+func (m *Mixer) Mix(buf []byte) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	channels := m.spec.Channels
+	frames := len(buf) / m.spec.FrameSize()
+	n := frames * channels
+	if n == 0 {
+		return
+	}
+
+	if cap(m.accum) < n {
+		m.accum = make([]float32, n)
+	}
+	accum := m.accum[:n]
+	for i := range accum {
+		accum[i] = 0
+	}
+
+	voices := m.voices[:0]
+	for _, v := range m.voices {
+		if !v.done {
+			v.mix(accum, channels)
+		}
+		if !v.done {
+			voices = append(voices, v)
+		}
+	}
+	for i := len(voices); i < len(m.voices); i++ {
+		m.voices[i] = nil
+	}
+	m.voices = voices
+
+	switch m.spec.Format {
+	case AudioF32:
+		out := (*[1 << 28]float32)(unsafe.Pointer(&buf[0]))[:n:n]
+		for i, s := range accum {
+			out[i] = clampSample(s * m.volume)
+		}
+	default:
+		out := (*[1 << 29]int16)(unsafe.Pointer(&buf[0]))[:n:n]
+		for i, s := range accum {
+			out[i] = int16(math.Floor(float64(clampSample(s*m.volume))*math.MaxInt16 + 0.5))
+		}
+	}
+}
+
+func clampSample(s float32) float32 {
+	if s < -1 {
+		return -1
+	} else if s > 1 {
+		return 1
+	}
+	return s
+}
+
+// mix adds the voice to accum, which has channels interleaved channels.
+func (v *Voice) mix(accum []float32, channels int) {
+	snd := v.sound
+	length := len(snd.Samples) / snd.Channels
+	if length == 0 {
+		v.done = true
+		return
+	}
+
+	left, right := v.volume, v.volume
+	if v.pan < 0 {
+		right *= 1 + v.pan
+	} else {
+		left *= 1 - v.pan
+	}
+
+	for f := 0; f < len(accum)/channels; f++ {
+		if v.pos >= float64(length) {
+			if !v.loop {
+				v.done = true
+				return
+			}
+			// The step can be longer than the sound, when it is
+			// short and has a higher rate than the device.
+			v.pos = math.Mod(v.pos, float64(length))
+		}
+
+		i := int(v.pos)
+		frac := float32(v.pos - float64(i))
+		next := i + 1
+		if next >= length {
+			next = i
+			if v.loop {
+				next = 0
+			}
+		}
+
+		a, b := snd.Samples[i*snd.Channels:], snd.Samples[next*snd.Channels:]
+		l := a[0] + (b[0]-a[0])*frac
+		r := l
+		if snd.Channels > 1 {
+			r = a[1] + (b[1]-a[1])*frac
+		}
+
+		out := accum[f*channels:]
+		if channels == 1 {
+			out[0] += (l*left + r*right) / 2
+		} else {
+			out[0] += l * left
+			out[1] += r * right
+		}
+		v.pos += v.step
+	}
+}
@@ -0,0 +1,131 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"math"
+	"testing"
+	"unsafe"
+)
+
+func TestMixLoopShorterThanStep(t *testing.T) {
+	m := NewMixerWithSpec(AudioSpec{Freq: 12000, Format: AudioF32, Channels: 1})
+	v, err := m.Play(&Sound{Samples: []float32{0.5}, Channels: 1, Freq: 48000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.SetLoop(true)
+
+	const frames = 16
+	buf := make([]byte, frames*4)
+	m.Mix(buf)
+
+	out := (*[frames]float32)(unsafe.Pointer(&buf[0]))
+	for i, s := range out {
+		if s != 0.5 {
+			t.Fatalf("sample %d is %v, expected 0.5", i, s)
+		}
+	}
+	if !v.Playing() {
+		t.Fatal("looping voice stopped")
+	}
+}
+
+// mixF32 mixes frames of float samples with m.
+func mixF32(m *Mixer, frames int) []float32 {
+	n := frames * m.spec.Channels
+	buf := make([]byte, n*4)
+	m.Mix(buf)
+	return (*[1 << 20]float32)(unsafe.Pointer(&buf[0]))[:n:n]
+}
+
+func constantSound(v float32, channels, frames int) *Sound {
+	s := &Sound{Samples: make([]float32, channels*frames), Channels: channels, Freq: 48000}
+	for i := range s.Samples {
+		s.Samples[i] = v
+	}
+	return s
+}
+
+func TestMixVolumeAndPan(t *testing.T) {
+	tests := []struct {
+		name        string
+		volume, pan float32
+		master      float32
+		left, right float32
+		channels    int
+	}{
+		{"center", 1, 0, 1, 0.5, 0.5, 1},
+		{"voice volume", 0.5, 0, 1, 0.25, 0.25, 1},
+		{"master volume", 1, 0, 0.5, 0.25, 0.25, 1},
+		{"left", 1, -1, 1, 0.5, 0, 1},
+		{"right", 1, 1, 1, 0, 0.5, 1},
+		{"half right", 1, 0.5, 1, 0.25, 0.5, 1},
+		{"clamped pan", 1, -2, 1, 0.5, 0, 1},
+		{"stereo sound", 0.5, 1, 1, 0, 0.25, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMixerWithSpec(AudioSpec{Freq: 48000, Format: AudioF32, Channels: 2})
+			m.SetVolume(tt.master)
+
+			v, err := m.Play(constantSound(0.5, tt.channels, 64))
+			if err != nil {
+				t.Fatal(err)
+			}
+			v.SetVolume(tt.volume)
+			v.SetPan(tt.pan)
+
+			out := mixF32(m, 16)
+			for i := 0; i < len(out); i += 2 {
+				if out[i] != tt.left || out[i+1] != tt.right {
+					t.Fatalf("frame %d is (%v, %v), expected (%v, %v)", i/2, out[i], out[i+1], tt.left, tt.right)
+				}
+			}
+		})
+	}
+}
+
+func TestMixClipping(t *testing.T) {
+	tests := []struct {
+		name   string
+		format AudioFormat
+		value  float32
+		want   float64
+	}{
+		{"f32 positive", AudioF32, 0.75, 1},
+		{"f32 negative", AudioF32, -0.75, -1},
+		{"s16 positive", AudioS16, 0.75, math.MaxInt16},
+		{"s16 negative", AudioS16, -0.75, -math.MaxInt16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMixerWithSpec(AudioSpec{Freq: 48000, Format: tt.format, Channels: 1})
+			for i := 0; i < 2; i++ {
+				if _, err := m.Play(constantSound(tt.value, 1, 64)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			const frames = 16
+			buf := make([]byte, frames*m.spec.FrameSize())
+			m.Mix(buf)
+
+			for i := 0; i < frames; i++ {
+				var s float64
+				if tt.format == AudioF32 {
+					s = float64((*[frames]float32)(unsafe.Pointer(&buf[0]))[i])
+				} else {
+					s = float64((*[frames]int16)(unsafe.Pointer(&buf[0]))[i])
+				}
+				if s != tt.want {
+					t.Fatalf("sample %d is %v, expected %v", i, s, tt.want)
+				}
+			}
+		})
+	}
+}
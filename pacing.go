@@ -0,0 +1,141 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"errors"
+	"time"
+	"unsafe"
+)
+
+// FrameStatistics reports the timing of presented frames.
+type FrameStatistics struct {
+	// Frames is the number of presented frames.
+	Frames uint64
+	// DroppedFrames is the number of frame intervals that were missed,
+	// compared to the target frame-rate or the display refresh rate when
+	// vsync is enabled.
+	DroppedFrames uint64
+	// FrameTime is the time between the two last presented frames.
+	FrameTime time.Duration
+	// AverageFrameTime is a moving average of the frame time.
+	AverageFrameTime time.Duration
+	// Jitter is a moving average of the frame time deviation from AverageFrameTime.
+	Jitter time.Duration
+}
+
+const frameStatsSmoothing = 0.1
+
+func ConfigWithVSync(b bool) Config {
+	return func() error {
+		windowConfig.vsync = b
+		return nil
+	}
+}
+
+// ConfigWithTargetFPS limits the frame-rate by delaying presentation, n = 0
+// disables the limit.
+func ConfigWithTargetFPS(n int) Config {
+	return func() error {
+		if n < 0 {
+			return errors.New("invalid target frame-rate")
+		}
+
+		windowConfig.targetFrameTime = 0
+		if n > 0 {
+			windowConfig.targetFrameTime = time.Second / time.Duration(n)
+		}
+		return nil
+	}
+}
+
+func FrameStats() FrameStatistics {
+	if defaultWindow == nil {
+		return FrameStatistics{}
+	}
+	return defaultWindow.FrameStats()
+}
+
+func (w *Window) FrameStats() FrameStatistics {
+	w.frameStatsLock.Lock()
+	defer w.frameStatsLock.Unlock()
+	return w.frameStats
+}
+
+func (w *Window) initFramePacing() {
+	w.frameStatsLock.Lock()
+	w.frameStats = FrameStatistics{}
+	w.frameStatsLock.Unlock()
+
+	w.pacingLock.Lock()
+	w.nextFrame = time.Time{}
+	w.pacingLock.Unlock()
+
+	w.lastFrame = time.Time{}
+	w.frameInterval = w.targetFrameTime
+
+	if w.frameInterval == 0 && w.vsync {
+		if !sdlGetWindowDisplayMode(w.window, unsafe.Pointer(&displayMode)) && displayMode.RefreshRate > 0 {
+			w.frameInterval = time.Second / time.Duration(displayMode.RefreshRate)
+		}
+	}
+}
+
+// waitForFrame sleeps until the next frame is due, it is called by the
+// presenting goroutine so the main thread is not blocked. The schedule is kept
+// relative to the first frame so that sleep inaccuracy does not accumulate.
+func (w *Window) waitForFrame() {
+	if w.targetFrameTime == 0 {
+		return
+	}
+
+	w.pacingLock.Lock()
+	now := time.Now()
+
+	var d time.Duration
+	if w.nextFrame.IsZero() || now.Sub(w.nextFrame) > w.targetFrameTime {
+		w.nextFrame = now
+	} else {
+		d = w.nextFrame.Sub(now)
+	}
+	w.nextFrame = w.nextFrame.Add(w.targetFrameTime)
+	w.pacingLock.Unlock()
+
+	time.Sleep(d)
+}
+
+func (w *Window) updateFrameStats() {
+	now := time.Now()
+
+	w.frameStatsLock.Lock()
+	defer w.frameStatsLock.Unlock()
+
+	stats := &w.frameStats
+	stats.Frames++
+	if w.lastFrame.IsZero() {
+		w.lastFrame = now
+		return
+	}
+
+	dt := now.Sub(w.lastFrame)
+	w.lastFrame = now
+	stats.FrameTime = dt
+
+	if stats.AverageFrameTime == 0 {
+		stats.AverageFrameTime = dt
+	} else {
+		stats.AverageFrameTime += time.Duration(frameStatsSmoothing * float64(dt-stats.AverageFrameTime))
+	}
+
+	deviation := dt - stats.AverageFrameTime
+	if deviation < 0 {
+		deviation = -deviation
+	}
+	stats.Jitter += time.Duration(frameStatsSmoothing * float64(deviation-stats.Jitter))
+
+	if w.frameInterval > 0 && dt > w.frameInterval+w.frameInterval/2 {
+		stats.DroppedFrames += uint64((dt+w.frameInterval/2)/w.frameInterval) - 1
+	}
+}
@@ -0,0 +1,134 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"errors"
+	"image"
+	"image/color"
+)
+
+const maxPaletteSize = 256
+
+// ConfigWithPalette puts the back-buffer in 8-bit indexed mode. Present then
+// only accepts *image.Paletted and interprets the pixel indices using the
+// palette set here or by SetPalette, the palette of the image is ignored.
+func ConfigWithPalette(p color.Palette) Config {
+	return func() error {
+		if len(p) > maxPaletteSize {
+			return errors.New("palette has more than 256 colors")
+		}
+		windowConfig.format = PixelFormatIndex8
+		windowConfig.palette = p
+		return nil
+	}
+}
+
+// SetPalette replaces the palette of an indexed back-buffer. The last
+// presented frame is presented again with the new palette, so palette
+// cycling and fades do not require the image to be presented again. That is
+// not counted as a new frame by FrameStats or the frame-rate limit.
+func SetPalette(p color.Palette) error {
+	if defaultWindow == nil {
+		return errWindowClosed
+	}
+	return defaultWindow.SetPalette(p)
+}
+
+func (w *Window) SetPalette(p color.Palette) error {
+	if w.format != PixelFormatIndex8 {
+		return errors.New("back-buffer is not paletted")
+	}
+	if len(p) > maxPaletteSize {
+		return errors.New("palette has more than 256 colors")
+	}
+
+	return sendCommand(false, func() error {
+		if w.texture == 0 {
+			return errWindowClosed
+		}
+		if w.lockedFrame != nil {
+			return errFrameLocked
+		}
+
+		w.setPalette(p)
+
+		// The last frame is not presented again if the back-buffer was
+		// resized since.
+		if w.indexBuffer == nil || !w.acceptsFrame(w.indexBuffer) {
+			return nil
+		}
+		if err := w.presentIndexBuffer(); err != nil {
+			return err
+		}
+		w.callFrameHook(w.frameHookFunc(), w.indexBuffer)
+		return nil
+	})
+}
+
+func (w *Window) setPalette(p color.Palette) {
+	w.devicePalette = make(color.Palette, maxPaletteSize)
+	for i := range w.devicePalette {
+		w.devicePalette[i] = color.RGBA{}
+	}
+	copy(w.devicePalette, p)
+
+	w.palette = [maxPaletteSize][4]uint8{}
+	for i, c := range p {
+		w.palette[i] = colorToRGBA(c)
+	}
+}
+
+// updateIndexBuffer copies the regions of src to the index buffer and
+// uploads them to the texture using the current palette.
+func (w *Window) updateIndexBuffer(src *image.Paletted, regions []image.Rectangle) error {
+	if w.indexBuffer == nil || w.indexBuffer.Rect.Size() != src.Rect.Size() {
+		w.indexBuffer = image.NewPaletted(image.Rectangle{Max: src.Rect.Size()}, nil)
+		w.expandBuffer = image.NewRGBA(w.indexBuffer.Rect)
+		regions = []image.Rectangle{w.indexBuffer.Rect}
+	}
+
+	for _, r := range regions {
+		copyIndices(w.indexBuffer, src, r)
+	}
+
+	for _, r := range regions {
+		if err := w.expandIndexBuffer(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyIndices(dst, src *image.Paletted, r image.Rectangle) {
+	w := r.Dx()
+	p := r.Min.Add(src.Rect.Min)
+
+	for y := 0; y < r.Dy(); y++ {
+		copy(dst.Pix[dst.PixOffset(r.Min.X, r.Min.Y+y):][:w], src.Pix[src.PixOffset(p.X, p.Y+y):])
+	}
+}
+
+func (w *Window) presentIndexBuffer() error {
+	if err := w.expandIndexBuffer(w.indexBuffer.Rect); err != nil {
+		return err
+	}
+	return w.redraw()
+}
+
+func (w *Window) expandIndexBuffer(r image.Rectangle) error {
+	dst, src := w.expandBuffer, w.indexBuffer
+	width := r.Dx()
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		s := src.Pix[src.PixOffset(r.Min.X, y):]
+		d := dst.Pix[dst.PixOffset(r.Min.X, y):]
+
+		for x := 0; x < width; x++ {
+			copy(d[x*4:x*4+4], w.palette[s[x]][:])
+		}
+	}
+	return w.updateTexture(dst, r)
+}
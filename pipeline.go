@@ -0,0 +1,268 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"sync"
+)
+
+// ErrPipelineFull is returned by TryPresent when all back-buffers of the
+// pipeline are waiting to be uploaded.
+var ErrPipelineFull = errors.New("all frame buffers are in use")
+
+// ConfigWithPipeline enables asynchronous presentation using a ring of depth
+// back-buffers owned by vsdl. Present copies the frame into a free buffer and
+// returns without waiting for the frame to be uploaded, blocking only when all
+// buffers are in use. The returned WaitGroup is done when the frame has been
+// uploaded, if that fails the error is returned by the next Present or
+// TryPresent.
+func ConfigWithPipeline(depth int) Config {
+	return func() error {
+		if depth < 0 {
+			return errors.New("invalid pipeline depth")
+		}
+		windowConfig.pipelineDepth = depth
+		return nil
+	}
+}
+
+// TryPresent works like Present but returns ErrPipelineFull, instead of
+// blocking, when pipelining is enabled and all back-buffers are in use.
+func TryPresent(img image.Image) (*sync.WaitGroup, error) {
+	if defaultWindow == nil {
+		return new(sync.WaitGroup), errWindowClosed
+	}
+	return defaultWindow.TryPresent(img)
+}
+
+func (w *Window) TryPresent(img image.Image) (*sync.WaitGroup, error) {
+	return w.presentRegions(img, []image.Rectangle{img.Bounds()}, false)
+}
+
+func (w *Window) presentPipelined(img image.Image, regions []image.Rectangle, full, block bool) (*sync.WaitGroup, error) {
+	wg := new(sync.WaitGroup)
+
+	if err := w.takePipelineError(); err != nil {
+		return wg, err
+	}
+
+	var frame image.Image
+	if block {
+		frame = <-w.frameBuffers
+	} else {
+		select {
+		case frame = <-w.frameBuffers:
+		default:
+			return wg, ErrPipelineFull
+		}
+	}
+
+	if size := img.Bounds().Size(); frame.Bounds().Size() != size {
+		frame = w.newFrameBuffer(size)
+	}
+
+	// The frame hook is called with the frame buffer after the upload, so it
+	// needs the whole frame and not only the regions.
+	hook := w.frameHookFunc()
+	copyRegions := regions
+	if hook != nil {
+		copyRegions = []image.Rectangle{{Max: img.Bounds().Size()}}
+	}
+
+	if err := copyToFrameBuffer(frame, img, copyRegions); err != nil {
+		w.frameBuffers <- frame
+		return wg, err
+	}
+	w.waitForFrame()
+
+	wg.Add(1)
+	w.queueUpload(func() {
+		err := w.uploadFrameBuffer(frame, regions, full)
+		if err == nil {
+			w.callFrameHook(hook, frame)
+		}
+		w.frameBuffers <- frame
+		wg.Done()
+
+		if err == nil {
+			err = w.renderTexture()
+		}
+		if err != nil {
+			w.setPipelineError(err)
+		}
+	})
+	return wg, nil
+}
+
+var (
+	uploadWindows     = map[*Window]struct{}{}
+	uploadWindowsLock sync.Mutex
+	uploadSignal      = make(chan struct{}, 1)
+)
+
+// queueUpload queues f to be run on the main thread. Every queued upload holds
+// a frame buffer, so the queue of a window never fills up and back-pressure
+// comes from the frame ring alone.
+func (w *Window) queueUpload(f func()) {
+	w.uploads <- f
+
+	uploadWindowsLock.Lock()
+	uploadWindows[w] = struct{}{}
+	uploadWindowsLock.Unlock()
+
+	select {
+	case uploadSignal <- struct{}{}:
+	default:
+	}
+}
+
+// runUploads runs the queued uploads of all windows on the main thread. It is
+// called before every command, so frames are uploaded before commands that
+// were sent after them.
+func runUploads() {
+	uploadWindowsLock.Lock()
+	pending := uploadWindows
+	uploadWindows = map[*Window]struct{}{}
+	uploadWindowsLock.Unlock()
+
+	for w := range pending {
+		for len(w.uploads) > 0 {
+			(<-w.uploads)()
+		}
+	}
+}
+
+// uploadFrameBuffer uploads the regions of a pipelined frame, it must be called
+// on the main thread.
+func (w *Window) uploadFrameBuffer(frame image.Image, regions []image.Rectangle, full bool) error {
+	if w.texture == 0 {
+		return errWindowClosed
+	}
+	if w.lockedFrame != nil {
+		return errFrameLocked
+	}
+	if !w.acceptsFrame(frame) {
+		return ErrBackBufferResized
+	}
+
+	if err := w.updateFrame(frame, regions); err != nil {
+		return err
+	}
+	w.frameUploaded(full)
+	return nil
+}
+
+// setPipelineError keeps the first error of the asynchronous uploads until it
+// is returned by takePipelineError.
+func (w *Window) setPipelineError(err error) {
+	w.pipelineErrLock.Lock()
+	if w.pipelineErr == nil {
+		w.pipelineErr = err
+	}
+	w.pipelineErrLock.Unlock()
+}
+
+func (w *Window) takePipelineError() error {
+	w.pipelineErrLock.Lock()
+	defer w.pipelineErrLock.Unlock()
+
+	err := w.pipelineErr
+	w.pipelineErr = nil
+	return err
+}
+
+func (w *Window) newFrameBuffer(size image.Point) image.Image {
+	r := image.Rectangle{Max: size}
+
+	switch w.format {
+	case PixelFormatIndex8:
+		return image.NewPaletted(r, nil)
+	case PixelFormatARGB8888, PixelFormatRGB888:
+		return NewBGRA(r)
+	case PixelFormatRGB565:
+		return NewRGB565(r)
+	case PixelFormatYV12, PixelFormatIYUV:
+		return image.NewYCbCr(r, image.YCbCrSubsampleRatio420)
+	case PixelFormatNV12:
+		return NewNV12(r)
+	default:
+		return image.NewRGBA(r)
+	}
+}
+
+// copyToFrameBuffer copies the regions of img to the frame buffer dst,
+// converting the pixels if needed.
+func copyToFrameBuffer(dst, img image.Image, regions []image.Rectangle) error {
+	b := img.Bounds()
+
+	switch d := dst.(type) {
+	case *image.RGBA:
+		convertToRGBA(d, img, regions)
+	case *image.Paletted:
+		src, ok := img.(*image.Paletted)
+		if !ok {
+			return errors.New("invalid image format, expected *image.Paletted")
+		}
+		for _, r := range regions {
+			p := r.Min.Add(b.Min)
+			copyRows(d.Pix[d.PixOffset(r.Min.X, r.Min.Y):], d.Stride, src.Pix[src.PixOffset(p.X, p.Y):], src.Stride, r.Dx(), r.Dy())
+		}
+	case *image.YCbCr:
+		src, ok := img.(*image.YCbCr)
+		if !ok || src.SubsampleRatio != image.YCbCrSubsampleRatio420 {
+			return errors.New("invalid image format, expected *image.YCbCr with 4:2:0 subsampling")
+		}
+		for _, r := range regions {
+			p := r.Min.Add(b.Min)
+			cw, ch := (r.Dx()+1)/2, (r.Dy()+1)/2
+			copyRows(d.Y[d.YOffset(r.Min.X, r.Min.Y):], d.YStride, src.Y[src.YOffset(p.X, p.Y):], src.YStride, r.Dx(), r.Dy())
+			copyRows(d.Cb[d.COffset(r.Min.X, r.Min.Y):], d.CStride, src.Cb[src.COffset(p.X, p.Y):], src.CStride, cw, ch)
+			copyRows(d.Cr[d.COffset(r.Min.X, r.Min.Y):], d.CStride, src.Cr[src.COffset(p.X, p.Y):], src.CStride, cw, ch)
+		}
+	case *BGRA:
+		if src, ok := img.(*BGRA); ok {
+			for _, r := range regions {
+				p := r.Min.Add(b.Min)
+				copyRows(d.Pix[d.PixOffset(r.Min.X, r.Min.Y):], d.Stride, src.Pix[src.PixOffset(p.X, p.Y):], src.Stride, r.Dx()*4, r.Dy())
+			}
+			return nil
+		}
+		drawRegions(d, img, regions)
+	case *RGB565:
+		if src, ok := img.(*RGB565); ok {
+			for _, r := range regions {
+				p := r.Min.Add(b.Min)
+				copyRows(d.Pix[d.PixOffset(r.Min.X, r.Min.Y):], d.Stride, src.Pix[src.PixOffset(p.X, p.Y):], src.Stride, r.Dx()*2, r.Dy())
+			}
+			return nil
+		}
+		drawRegions(d, img, regions)
+	case *NV12:
+		if src, ok := img.(*NV12); ok {
+			h := b.Dy()
+			copyRows(d.Pix, d.Stride, src.Pix, src.Stride, b.Dx(), h)
+			copyRows(d.Pix[d.COffset(0, 0):], d.Stride, src.Pix[src.COffset(b.Min.X, b.Min.Y):], src.Stride, b.Dx()+b.Dx()&1, (h+1)/2)
+			return nil
+		}
+		drawRegions(d, img, regions)
+	}
+	return nil
+}
+
+func drawRegions(dst draw.Image, img image.Image, regions []image.Rectangle) {
+	b := img.Bounds()
+	for _, r := range regions {
+		draw.Draw(dst, r, img, r.Min.Add(b.Min), draw.Src)
+	}
+}
+
+func copyRows(dst []uint8, dstStride int, src []uint8, srcStride, width, height int) {
+	for y := 0; y < height; y++ {
+		copy(dst[y*dstStride:y*dstStride+width], src[y*srcStride:])
+	}
+}
@@ -1,3 +1,5 @@
+// +build !headless
+
 /* This Source Code Form is subject to the terms of the Mozilla Public
  * License, v. 2.0. If a copy of the MPL was not distributed with this
  * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
@@ -11,35 +13,95 @@ import (
 	"runtime"
 )
 
-var (
-	libraryHandle                libHandle
-	libraryName, removeDirectory string
-)
-
 var (
 	sdlInitProc,
 	sdlQuitProc,
 	sdlGetErrorProc,
 	sdlGetVersionProc,
-	sdlCreateWindowAndRendererProc,
+	sdlCreateWindowProc,
+	sdlCreateRendererProc,
 	sdlShowCursorProc,
 	sdlDestroyRendererProc,
 	sdlDestroyWindowProc,
 	sdlGetWindowFlagsProc,
+	sdlGetWindowIDProc,
+	sdlSetWindowTitleProc,
+	sdlGetWindowTitleProc,
+	sdlCreateRGBSurfaceWithFormatFromProc,
+	sdlFreeSurfaceProc,
+	sdlSetWindowIconProc,
+	sdlSetWindowPositionProc,
+	sdlGetWindowPositionProc,
+	sdlSetWindowSizeProc,
+	sdlGetWindowSizeProc,
+	sdlMinimizeWindowProc,
+	sdlMaximizeWindowProc,
+	sdlRestoreWindowProc,
+	sdlRaiseWindowProc,
+	sdlSetWindowBorderedProc,
+	sdlSetWindowOpacityProc,
+	sdlGetWindowOpacityProc,
+	sdlGetWindowDisplayModeProc,
 	sdlSetWindowFullscreenProc,
+	sdlSetWindowDisplayModeProc,
+	sdlGetNumVideoDisplaysProc,
+	sdlGetDisplayNameProc,
+	sdlGetDisplayBoundsProc,
+	sdlGetDisplayUsableBoundsProc,
+	sdlGetDisplayDPIProc,
+	sdlGetNumDisplayModesProc,
+	sdlGetDisplayModeProc,
+	sdlGetDesktopDisplayModeProc,
+	sdlGetClosestDisplayModeProc,
 	sdlCreateTextureProc,
 	sdlDestroyTextureProc,
 	sdlUpdateTextureProc,
+	sdlUpdateYUVTextureProc,
+	sdlLockTextureProc,
+	sdlUnlockTextureProc,
 	sdlRenderCopyProc,
 	sdlRenderPresentProc,
+	sdlRenderReadPixelsProc,
+	sdlGetRendererOutputSizeProc,
 	sdlRenderSetLogicalSizeProc,
-	sdlPollEventProc uintptr
-)
+	sdlPollEventProc,
+	sdlStartTextInputProc,
+	sdlStopTextInputProc,
+	sdlSetTextInputRectProc,
+	sdlGetKeyFromScancodeProc,
+	sdlGetKeyboardStateProc,
+	sdlGetModStateProc,
+	sdlGetMouseStateProc,
+	sdlNumJoysticksProc,
+	sdlIsGameControllerProc,
+	sdlGameControllerOpenProc,
+	sdlGameControllerCloseProc,
+	sdlGameControllerNameProc,
+	sdlGameControllerGetJoystickProc,
+	sdlGameControllerGetButtonProc,
+	sdlGameControllerGetAxisProc,
+	sdlGameControllerAddMappingProc,
+	sdlJoystickInstanceIDProc,
+	sdlJoystickGetGUIDProc,
+	sdlErrorProc,
+	sdlGetNumAudioDevicesProc,
+	sdlGetAudioDeviceNameProc,
+	sdlOpenAudioDeviceProc,
+	sdlPauseAudioDeviceProc,
+	sdlCloseAudioDeviceProc,
+	sdlQueueAudioProc,
+	sdlGetQueuedAudioSizeProc,
+	sdlClearQueuedAudioProc uintptr
 
-const sdl_WINDOW_FULLSCREEN uint32 = 0x00000001
-const sdl_WINDOW_FULLSCREEN_DESKTOP uint32 = sdl_WINDOW_FULLSCREEN | 0x00001000
+	// Optional procs, not available in the embedded SDL version.
+	sdlGameControllerRumbleProc,
+	sdlGameControllerRumbleTriggersProc,
+	sdlGameControllerHasRumbleProc,
+	sdlGameControllerHasRumbleTriggersProc,
+	sdlSetWindowAlwaysOnTopProc uintptr
+)
 
-const defaultFullscreenFlag = sdl_WINDOW_FULLSCREEN_DESKTOP
+const sdl_UNSUPPORTED = 4
 
 func loadEmbeddedLibrary(name string) (libHandle, error) {
 	if name != "" {
@@ -91,7 +153,11 @@ func initProcs() error {
 		return err
 	}
 
-	if sdlCreateWindowAndRendererProc, err = getProc("SDL_CreateWindowAndRenderer"); err != nil {
+	if sdlCreateWindowProc, err = getProc("SDL_CreateWindow"); err != nil {
+		return err
+	}
+
+	if sdlCreateRendererProc, err = getProc("SDL_CreateRenderer"); err != nil {
 		return err
 	}
 
@@ -111,10 +177,122 @@ func initProcs() error {
 		return err
 	}
 
+	if sdlGetWindowIDProc, err = getProc("SDL_GetWindowID"); err != nil {
+		return err
+	}
+
+	if sdlSetWindowTitleProc, err = getProc("SDL_SetWindowTitle"); err != nil {
+		return err
+	}
+
+	if sdlGetWindowTitleProc, err = getProc("SDL_GetWindowTitle"); err != nil {
+		return err
+	}
+
+	if sdlCreateRGBSurfaceWithFormatFromProc, err = getProc("SDL_CreateRGBSurfaceWithFormatFrom"); err != nil {
+		return err
+	}
+
+	if sdlFreeSurfaceProc, err = getProc("SDL_FreeSurface"); err != nil {
+		return err
+	}
+
+	if sdlSetWindowIconProc, err = getProc("SDL_SetWindowIcon"); err != nil {
+		return err
+	}
+
+	if sdlSetWindowPositionProc, err = getProc("SDL_SetWindowPosition"); err != nil {
+		return err
+	}
+
+	if sdlGetWindowPositionProc, err = getProc("SDL_GetWindowPosition"); err != nil {
+		return err
+	}
+
+	if sdlSetWindowSizeProc, err = getProc("SDL_SetWindowSize"); err != nil {
+		return err
+	}
+
+	if sdlGetWindowSizeProc, err = getProc("SDL_GetWindowSize"); err != nil {
+		return err
+	}
+
+	if sdlMinimizeWindowProc, err = getProc("SDL_MinimizeWindow"); err != nil {
+		return err
+	}
+
+	if sdlMaximizeWindowProc, err = getProc("SDL_MaximizeWindow"); err != nil {
+		return err
+	}
+
+	if sdlRestoreWindowProc, err = getProc("SDL_RestoreWindow"); err != nil {
+		return err
+	}
+
+	if sdlRaiseWindowProc, err = getProc("SDL_RaiseWindow"); err != nil {
+		return err
+	}
+
+	if sdlSetWindowBorderedProc, err = getProc("SDL_SetWindowBordered"); err != nil {
+		return err
+	}
+
+	if sdlSetWindowOpacityProc, err = getProc("SDL_SetWindowOpacity"); err != nil {
+		return err
+	}
+
+	if sdlGetWindowOpacityProc, err = getProc("SDL_GetWindowOpacity"); err != nil {
+		return err
+	}
+
+	if sdlGetWindowDisplayModeProc, err = getProc("SDL_GetWindowDisplayMode"); err != nil {
+		return err
+	}
+
 	if sdlSetWindowFullscreenProc, err = getProc("SDL_SetWindowFullscreen"); err != nil {
 		return err
 	}
 
+	if sdlSetWindowDisplayModeProc, err = getProc("SDL_SetWindowDisplayMode"); err != nil {
+		return err
+	}
+
+	if sdlGetNumVideoDisplaysProc, err = getProc("SDL_GetNumVideoDisplays"); err != nil {
+		return err
+	}
+
+	if sdlGetDisplayNameProc, err = getProc("SDL_GetDisplayName"); err != nil {
+		return err
+	}
+
+	if sdlGetDisplayBoundsProc, err = getProc("SDL_GetDisplayBounds"); err != nil {
+		return err
+	}
+
+	if sdlGetDisplayUsableBoundsProc, err = getProc("SDL_GetDisplayUsableBounds"); err != nil {
+		return err
+	}
+
+	if sdlGetDisplayDPIProc, err = getProc("SDL_GetDisplayDPI"); err != nil {
+		return err
+	}
+
+	if sdlGetNumDisplayModesProc, err = getProc("SDL_GetNumDisplayModes"); err != nil {
+		return err
+	}
+
+	if sdlGetDisplayModeProc, err = getProc("SDL_GetDisplayMode"); err != nil {
+		return err
+	}
+
+	if sdlGetDesktopDisplayModeProc, err = getProc("SDL_GetDesktopDisplayMode"); err != nil {
+		return err
+	}
+
+	if sdlGetClosestDisplayModeProc, err = getProc("SDL_GetClosestDisplayMode"); err != nil {
+		return err
+	}
+
 	if sdlCreateTextureProc, err = getProc("SDL_CreateTexture"); err != nil {
 		return err
 	}
@@ -127,6 +305,18 @@ func initProcs() error {
 		return err
 	}
 
+	if sdlUpdateYUVTextureProc, err = getProc("SDL_UpdateYUVTexture"); err != nil {
+		return err
+	}
+
+	if sdlLockTextureProc, err = getProc("SDL_LockTexture"); err != nil {
+		return err
+	}
+
+	if sdlUnlockTextureProc, err = getProc("SDL_UnlockTexture"); err != nil {
+		return err
+	}
+
 	if sdlRenderCopyProc, err = getProc("SDL_RenderCopy"); err != nil {
 		return err
 	}
@@ -135,6 +325,14 @@ func initProcs() error {
 		return err
 	}
 
+	if sdlRenderReadPixelsProc, err = getProc("SDL_RenderReadPixels"); err != nil {
+		return err
+	}
+
+	if sdlGetRendererOutputSizeProc, err = getProc("SDL_GetRendererOutputSize"); err != nil {
+		return err
+	}
+
 	if sdlRenderSetLogicalSizeProc, err = getProc("SDL_RenderSetLogicalSize"); err != nil {
 		return err
 	}
@@ -143,5 +341,119 @@ func initProcs() error {
 		return err
 	}
 
+	if sdlStartTextInputProc, err = getProc("SDL_StartTextInput"); err != nil {
+		return err
+	}
+
+	if sdlStopTextInputProc, err = getProc("SDL_StopTextInput"); err != nil {
+		return err
+	}
+
+	if sdlSetTextInputRectProc, err = getProc("SDL_SetTextInputRect"); err != nil {
+		return err
+	}
+
+	if sdlGetKeyFromScancodeProc, err = getProc("SDL_GetKeyFromScancode"); err != nil {
+		return err
+	}
+
+	if sdlGetKeyboardStateProc, err = getProc("SDL_GetKeyboardState"); err != nil {
+		return err
+	}
+
+	if sdlGetModStateProc, err = getProc("SDL_GetModState"); err != nil {
+		return err
+	}
+
+	if sdlGetMouseStateProc, err = getProc("SDL_GetMouseState"); err != nil {
+		return err
+	}
+
+	if sdlNumJoysticksProc, err = getProc("SDL_NumJoysticks"); err != nil {
+		return err
+	}
+
+	if sdlIsGameControllerProc, err = getProc("SDL_IsGameController"); err != nil {
+		return err
+	}
+
+	if sdlGameControllerOpenProc, err = getProc("SDL_GameControllerOpen"); err != nil {
+		return err
+	}
+
+	if sdlGameControllerCloseProc, err = getProc("SDL_GameControllerClose"); err != nil {
+		return err
+	}
+
+	if sdlGameControllerNameProc, err = getProc("SDL_GameControllerName"); err != nil {
+		return err
+	}
+
+	if sdlGameControllerGetJoystickProc, err = getProc("SDL_GameControllerGetJoystick"); err != nil {
+		return err
+	}
+
+	if sdlGameControllerGetButtonProc, err = getProc("SDL_GameControllerGetButton"); err != nil {
+		return err
+	}
+
+	if sdlGameControllerGetAxisProc, err = getProc("SDL_GameControllerGetAxis"); err != nil {
+		return err
+	}
+
+	if sdlGameControllerAddMappingProc, err = getProc("SDL_GameControllerAddMapping"); err != nil {
+		return err
+	}
+
+	if sdlJoystickInstanceIDProc, err = getProc("SDL_JoystickInstanceID"); err != nil {
+		return err
+	}
+
+	if sdlJoystickGetGUIDProc, err = getProc("SDL_JoystickGetGUID"); err != nil {
+		return err
+	}
+
+	if sdlErrorProc, err = getProc("SDL_Error"); err != nil {
+		return err
+	}
+
+	if sdlGetNumAudioDevicesProc, err = getProc("SDL_GetNumAudioDevices"); err != nil {
+		return err
+	}
+
+	if sdlGetAudioDeviceNameProc, err = getProc("SDL_GetAudioDeviceName"); err != nil {
+		return err
+	}
+
+	if sdlOpenAudioDeviceProc, err = getProc("SDL_OpenAudioDevice"); err != nil {
+		return err
+	}
+
+	if sdlPauseAudioDeviceProc, err = getProc("SDL_PauseAudioDevice"); err != nil {
+		return err
+	}
+
+	if sdlCloseAudioDeviceProc, err = getProc("SDL_CloseAudioDevice"); err != nil {
+		return err
+	}
+
+	if sdlQueueAudioProc, err = getProc("SDL_QueueAudio"); err != nil {
+		return err
+	}
+
+	if sdlGetQueuedAudioSizeProc, err = getProc("SDL_GetQueuedAudioSize"); err != nil {
+		return err
+	}
+
+	if sdlClearQueuedAudioProc, err = getProc("SDL_ClearQueuedAudio"); err != nil {
+		return err
+	}
+
+	sdlGameControllerRumbleProc, _ = getProc("SDL_GameControllerRumble")
+	sdlGameControllerRumbleTriggersProc, _ = getProc("SDL_GameControllerRumbleTriggers")
+	sdlGameControllerHasRumbleProc, _ = getProc("SDL_GameControllerHasRumble")
+	sdlGameControllerHasRumbleTriggersProc, _ = getProc("SDL_GameControllerHasRumbleTriggers")
+	sdlSetWindowAlwaysOnTopProc, _ = getProc("SDL_SetWindowAlwaysOnTop")
+
 	return nil
 }
@@ -0,0 +1,579 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	colorpalette "image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"sync"
+	"time"
+)
+
+type RecordFormat int
+
+const (
+	// RecordGIF writes an animated GIF, frames are quantized to the web-safe palette.
+	RecordGIF RecordFormat = iota
+	// RecordAPNG writes an animated PNG.
+	RecordAPNG
+	// RecordY4M writes a raw 4:2:0 YUV4MPEG2 stream with a constant frame-rate.
+	// Frames are repeated or dropped to match the time they were presented.
+	RecordY4M
+)
+
+type RecorderOption func(r *Recorder) error
+
+// ErrRecordingTruncated is returned by Stop when a GIF or APNG recording
+// reached the memory limit, the frames recorded until then are written.
+var ErrRecordingTruncated = errors.New("recording reached the memory limit")
+
+const defaultRecordMaxMemory = 256 << 20
+
+// recordQueueSize is the number of frames that can wait to be encoded, frames
+// are dropped while the queue is full.
+const recordQueueSize = 8
+
+// RecordWithFrameSkip only records every n+1 presented frame.
+func RecordWithFrameSkip(n int) RecorderOption {
+	return func(r *Recorder) error {
+		if n < 0 {
+			return errors.New("invalid frame skip")
+		}
+		r.frameSkip = n
+		return nil
+	}
+}
+
+// RecordWithMaxDuration stops recording frames when d has elapsed since the
+// first recorded frame. Stop must still be called to write the recording.
+func RecordWithMaxDuration(d time.Duration) RecorderOption {
+	return func(r *Recorder) error {
+		r.maxDuration = d
+		return nil
+	}
+}
+
+// RecordWithMaxMemory limits the memory used by GIF and APNG recordings, which
+// are kept in memory until Stop writes them. The default is 256 MiB.
+func RecordWithMaxMemory(n int) RecorderOption {
+	return func(r *Recorder) error {
+		if n <= 0 {
+			return errors.New("invalid memory limit")
+		}
+		r.maxMemory = n
+		return nil
+	}
+}
+
+// RecordWithFrameRate sets the frame-rate of Y4M streams, the default is 60.
+func RecordWithFrameRate(fps int) RecorderOption {
+	return func(r *Recorder) error {
+		if fps <= 0 {
+			return errors.New("invalid frame-rate")
+		}
+		r.frameRate = fps
+		return nil
+	}
+}
+
+// Recorder writes presented frames to an animated image or video stream. The
+// frames are encoded on a separate goroutine, so recording does not delay the
+// presentation of frames.
+type Recorder struct {
+	lock sync.Mutex
+	w    *bufio.Writer
+	err  error
+
+	window   *Window
+	prevHook FrameHook
+	queue    chan recordedFrame
+	done     chan struct{}
+
+	format      RecordFormat
+	frameSkip   int
+	maxDuration time.Duration
+	maxMemory   int
+	frameRate   int
+
+	start     time.Time
+	frames    int
+	recorded  int
+	memory    int
+	recording bool
+	truncated bool
+	closed    bool
+
+	times    []time.Duration
+	gifFrame []*image.Paletted
+	pngFrame [][]byte
+	size     image.Point
+
+	yuv     *image.YCbCr
+	written int
+}
+
+type recordedFrame struct {
+	img *image.RGBA
+	t   time.Duration
+}
+
+func NewRecorder(w io.Writer, format RecordFormat, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		w:         bufio.NewWriter(w),
+		format:    format,
+		maxMemory: defaultRecordMaxMemory,
+		frameRate: 60,
+		recording: true,
+		queue:     make(chan recordedFrame, recordQueueSize),
+		done:      make(chan struct{}),
+	}
+
+	if format < RecordGIF || format > RecordY4M {
+		return nil, errors.New("invalid record format")
+	}
+
+	for _, opt := range opts {
+		if err := opt(r); err != nil {
+			return nil, err
+		}
+	}
+
+	go r.encode()
+	return r, nil
+}
+
+// Start records the frames presented to w. The recorder is called after the
+// current frame hook of the window, which is restored by Stop.
+func (r *Recorder) Start(w *Window) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.closed {
+		return errors.New("recorder is stopped")
+	}
+	if r.window != nil {
+		return errors.New("recorder is already started")
+	}
+
+	w.frameHookLock.Lock()
+	prev := w.frameHook
+	w.frameHook = func(frame image.Image) {
+		if prev != nil {
+			prev(frame)
+		}
+		r.Frame(frame)
+	}
+	w.frameHookLock.Unlock()
+
+	r.window, r.prevHook = w, prev
+	return nil
+}
+
+// Recording reports if frames passed to Frame are recorded. It becomes false
+// when the max duration or memory limit is reached, when recording failed and
+// after Stop.
+func (r *Recorder) Recording() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.recording
+}
+
+// Stop restores the frame hook replaced by Start and finishes the recording.
+// It must be called even if Start was not, to stop the encoder. The underlying
+// writer is not closed.
+func (r *Recorder) Stop() error {
+	r.lock.Lock()
+	if r.window != nil {
+		r.window.SetFrameHook(r.prevHook)
+		r.window, r.prevHook = nil, nil
+	}
+
+	if r.closed {
+		err := r.err
+		r.lock.Unlock()
+		return err
+	}
+	r.closed = true
+	r.recording = false
+	close(r.queue)
+	r.lock.Unlock()
+
+	// Wait for the queued frames to be encoded.
+	<-r.done
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	switch r.format {
+	case RecordGIF:
+		r.err = r.writeGIF()
+	case RecordAPNG:
+		r.err = r.writeAPNG()
+	}
+
+	if r.err == nil {
+		r.err = r.w.Flush()
+	}
+	if r.err == nil && r.truncated {
+		return ErrRecordingTruncated
+	}
+	return r.err
+}
+
+// Frame records a frame, it has the signature of a FrameHook so the recorder
+// can be combined with other hooks. The frame is copied and encoded later, it
+// is dropped if the encoder falls behind.
+func (r *Recorder) Frame(frame image.Image) {
+	now := time.Now()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.closed || r.err != nil || !r.recording {
+		return
+	}
+
+	n := r.frames
+	r.frames++
+	if n%(r.frameSkip+1) != 0 {
+		return
+	}
+
+	if r.recorded == 0 {
+		r.start = now
+		r.size = frame.Bounds().Size()
+	} else if frame.Bounds().Size() != r.size {
+		r.err = errors.New("frame size changed during recording")
+		r.recording = false
+		return
+	}
+
+	t := now.Sub(r.start)
+	if r.maxDuration > 0 && t > r.maxDuration {
+		r.recording = false
+		return
+	}
+
+	// The size of quantized GIF frames is known up front, APNG frames are
+	// accounted for when they have been encoded.
+	var size int
+	if r.format == RecordGIF {
+		size = r.size.X * r.size.Y
+	}
+	if !r.reserveMemory(size) {
+		return
+	}
+
+	select {
+	case r.queue <- recordedFrame{opaqueRGBA(frame), t}:
+		r.recorded++
+	default:
+		r.memory -= size
+	}
+}
+
+// encode encodes the queued frames until Stop closes the queue.
+func (r *Recorder) encode() {
+	defer close(r.done)
+
+	for f := range r.queue {
+		r.lock.Lock()
+		failed := r.err != nil
+		r.lock.Unlock()
+
+		if failed {
+			continue
+		}
+
+		if err := r.encodeFrame(f); err != nil {
+			r.lock.Lock()
+			r.err = err
+			r.recording = false
+			r.lock.Unlock()
+		}
+	}
+}
+
+func (r *Recorder) encodeFrame(f recordedFrame) error {
+	switch r.format {
+	case RecordGIF:
+		dst := image.NewPaletted(image.Rectangle{Max: r.size}, colorpalette.WebSafe)
+		draw.FloydSteinberg.Draw(dst, dst.Rect, f.img, image.Point{})
+		r.gifFrame = append(r.gifFrame, dst)
+		r.times = append(r.times, f.t)
+	case RecordAPNG:
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, f.img); err != nil {
+			return err
+		}
+
+		r.lock.Lock()
+		ok := r.reserveMemory(buf.Len())
+		r.lock.Unlock()
+
+		if ok {
+			r.pngFrame = append(r.pngFrame, buf.Bytes())
+			r.times = append(r.times, f.t)
+		}
+	case RecordY4M:
+		return r.writeY4MFrame(f.img, f.t)
+	}
+	return nil
+}
+
+// reserveMemory accounts for n bytes of a frame kept until Stop, it stops the
+// recording if that exceeds the memory limit. It is called with the lock held.
+func (r *Recorder) reserveMemory(n int) bool {
+	if r.memory+n > r.maxMemory {
+		r.truncated = true
+		r.recording = false
+		return false
+	}
+	r.memory += n
+	return true
+}
+
+// delays converts the frame timestamps to durations in units of 1/den
+// seconds. Rounding is done on the timestamps so errors do not accumulate.
+func (r *Recorder) delays(den int64, end time.Duration) []int64 {
+	ticks := func(t time.Duration) int64 {
+		return (int64(t)*den + int64(time.Second)/2) / int64(time.Second)
+	}
+
+	delays := make([]int64, len(r.times))
+	for i, t := range r.times {
+		next := end
+		if i+1 < len(r.times) {
+			next = r.times[i+1]
+		}
+		delays[i] = ticks(next) - ticks(t)
+	}
+	return delays
+}
+
+// lastFrameEnd estimates when the last frame stops being displayed.
+func (r *Recorder) lastFrameEnd() time.Duration {
+	n := len(r.times)
+	if n < 2 {
+		return time.Second / time.Duration(r.frameRate)
+	}
+	return r.times[n-1] + (r.times[n-1]-r.times[0])/time.Duration(n-1)
+}
+
+func (r *Recorder) writeGIF() error {
+	if len(r.gifFrame) == 0 {
+		return errors.New("no frames recorded")
+	}
+
+	delays := r.delays(100, r.lastFrameEnd())
+	anim := &gif.GIF{Image: r.gifFrame}
+
+	for _, d := range delays {
+		anim.Delay = append(anim.Delay, int(d))
+	}
+	return gif.EncodeAll(r.w, anim)
+}
+
+func (r *Recorder) writeAPNG() error {
+	if len(r.pngFrame) == 0 {
+		return errors.New("no frames recorded")
+	}
+
+	delays := r.delays(1000, r.lastFrameEnd())
+	seq := uint32(0)
+
+	if _, err := r.w.Write(r.pngFrame[0][:8]); err != nil {
+		return err
+	}
+
+	for i, data := range r.pngFrame {
+		chunks, err := pngChunks(data[8:])
+		if err != nil {
+			return err
+		}
+
+		if i == 0 {
+			for _, c := range chunks {
+				switch c.ty {
+				case "IHDR":
+					var actl [8]byte
+					binary.BigEndian.PutUint32(actl[0:], uint32(len(r.pngFrame)))
+
+					if err := writePNGChunk(r.w, c.ty, c.data); err != nil {
+						return err
+					}
+					if err := writePNGChunk(r.w, "acTL", actl[:]); err != nil {
+						return err
+					}
+					if err := writeFCTL(r.w, &seq, r.size, delays[i]); err != nil {
+						return err
+					}
+				case "IDAT":
+					if err := writePNGChunk(r.w, c.ty, c.data); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+
+		if err := writeFCTL(r.w, &seq, r.size, delays[i]); err != nil {
+			return err
+		}
+
+		for _, c := range chunks {
+			if c.ty != "IDAT" {
+				continue
+			}
+
+			fdat := make([]byte, 4+len(c.data))
+			binary.BigEndian.PutUint32(fdat, seq)
+			copy(fdat[4:], c.data)
+			seq++
+
+			if err := writePNGChunk(r.w, "fdAT", fdat); err != nil {
+				return err
+			}
+		}
+	}
+	return writePNGChunk(r.w, "IEND", nil)
+}
+
+type pngChunk struct {
+	ty   string
+	data []byte
+}
+
+func pngChunks(data []byte) ([]pngChunk, error) {
+	var chunks []pngChunk
+
+	for len(data) >= 12 {
+		n := binary.BigEndian.Uint32(data)
+		if uint64(n)+12 > uint64(len(data)) {
+			return nil, errors.New("invalid png chunk")
+		}
+
+		chunks = append(chunks, pngChunk{ty: string(data[4:8]), data: data[8 : 8+n]})
+		data = data[12+n:]
+	}
+	return chunks, nil
+}
+
+func writePNGChunk(w io.Writer, ty string, data []byte) error {
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(len(data)))
+	copy(header[4:], ty)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+
+	var footer [4]byte
+	binary.BigEndian.PutUint32(footer[:], crc.Sum32())
+
+	for _, b := range [][]byte{header[:], data, footer[:]} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFCTL(w io.Writer, seq *uint32, size image.Point, delay int64) error {
+	if delay > 0xffff {
+		delay = 0xffff
+	}
+
+	var fctl [26]byte
+	binary.BigEndian.PutUint32(fctl[0:], *seq)
+	binary.BigEndian.PutUint32(fctl[4:], uint32(size.X))
+	binary.BigEndian.PutUint32(fctl[8:], uint32(size.Y))
+	binary.BigEndian.PutUint16(fctl[20:], uint16(delay))
+	binary.BigEndian.PutUint16(fctl[22:], 1000)
+	*seq++
+
+	return writePNGChunk(w, "fcTL", fctl[:])
+}
+
+// opaqueRGBA copies frame to an opaque image, so that all frames are encoded
+// with the same PNG color type.
+func opaqueRGBA(frame image.Image) *image.RGBA {
+	b := frame.Bounds()
+	dst := image.NewRGBA(image.Rectangle{Max: b.Size()})
+	draw.Draw(dst, dst.Rect, image.Black, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Rect, frame, b.Min, draw.Over)
+	return dst
+}
+
+func (r *Recorder) writeY4MFrame(frame image.Image, t time.Duration) error {
+	if r.yuv == nil {
+		r.yuv = image.NewYCbCr(image.Rectangle{Max: r.size}, image.YCbCrSubsampleRatio420)
+		if _, err := fmt.Fprintf(r.w, "YUV4MPEG2 W%d H%d F%d:1 Ip A1:1 C420jpeg\n", r.size.X, r.size.Y, r.frameRate); err != nil {
+			return err
+		}
+	}
+
+	target := int((int64(t)*int64(r.frameRate)+int64(time.Second)/2)/int64(time.Second)) + 1
+	if target <= r.written {
+		return nil
+	}
+
+	toYCbCr420(r.yuv, frame)
+
+	for ; r.written < target; r.written++ {
+		if _, err := io.WriteString(r.w, "FRAME\n"); err != nil {
+			return err
+		}
+		for _, plane := range [][]uint8{r.yuv.Y, r.yuv.Cb, r.yuv.Cr} {
+			if _, err := r.w.Write(plane); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// toYCbCr420 converts src to dst, averaging the chroma of each 2x2 block.
+func toYCbCr420(dst *image.YCbCr, src image.Image) {
+	b := src.Bounds()
+	size := b.Size()
+
+	for cy := 0; cy < (size.Y+1)/2; cy++ {
+		for cx := 0; cx < (size.X+1)/2; cx++ {
+			var cb, cr, n int
+
+			for y := cy * 2; y < cy*2+2 && y < size.Y; y++ {
+				for x := cx * 2; x < cx*2+2 && x < size.X; x++ {
+					c := color.RGBAModel.Convert(src.At(b.Min.X+x, b.Min.Y+y)).(color.RGBA)
+					yy, u, v := color.RGBToYCbCr(c.R, c.G, c.B)
+
+					dst.Y[dst.YOffset(x, y)] = yy
+					cb += int(u)
+					cr += int(v)
+					n++
+				}
+			}
+
+			ci := dst.COffset(cx*2, cy*2)
+			dst.Cb[ci] = uint8(cb / n)
+			dst.Cr[ci] = uint8(cr / n)
+		}
+	}
+}
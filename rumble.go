@@ -0,0 +1,93 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+func rumbleDuration(d time.Duration) uint32 {
+	ms := d / time.Millisecond
+	if ms < 0 {
+		return 0
+	} else if ms > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(ms)
+}
+
+// Rumble starts a rumble effect on the low and high frequency motors, a new
+// call replaces the previous effect and zero intensity stops it. Requires
+// SDL 2.0.9 or later.
+func (c *Controller) Rumble(low, high uint16, duration time.Duration) error {
+	return sendCommand(false, func() error {
+		if c.handle == 0 {
+			return errors.New("controller is closed")
+		}
+		if sdlGameControllerRumble(c.handle, low, high, rumbleDuration(duration)) {
+			return sdlToGoError()
+		}
+		return nil
+	})
+}
+
+// RumbleTriggers starts a rumble effect in the triggers, like the ones on
+// Xbox One controllers. Requires SDL 2.0.14 or later.
+func (c *Controller) RumbleTriggers(left, right uint16, duration time.Duration) error {
+	return sendCommand(false, func() error {
+		if c.handle == 0 {
+			return errors.New("controller is closed")
+		}
+		if sdlGameControllerRumbleTriggers(c.handle, left, right, rumbleDuration(duration)) {
+			return sdlToGoError()
+		}
+		return nil
+	})
+}
+
+// errRumbleQuery is returned by HasRumble and HasRumbleTriggers when SDL can
+// not report rumble support.
+var errRumbleQuery = errors.New("querying rumble support requires SDL 2.0.18 or later")
+
+// HasRumble reports if the controller supports Rumble. SDL versions older
+// than 2.0.18 can not report it, false and an error is then returned. Testing
+// it by calling Rumble is possible, but it replaces the current effect.
+func (c *Controller) HasRumble() (bool, error) {
+	var b bool
+	err := sendCommand(false, func() error {
+		if c.handle == 0 {
+			return errors.New("controller is closed")
+		}
+
+		has := sdlGameControllerHasRumble(c.handle)
+		if has < 0 {
+			return errRumbleQuery
+		}
+		b = has != 0
+		return nil
+	})
+	return b, err
+}
+
+// HasRumbleTriggers reports if the controller supports RumbleTriggers, see
+// HasRumble for the SDL version requirement.
+func (c *Controller) HasRumbleTriggers() (bool, error) {
+	var b bool
+	err := sendCommand(false, func() error {
+		if c.handle == 0 {
+			return errors.New("controller is closed")
+		}
+
+		has := sdlGameControllerHasRumbleTriggers(c.handle)
+		if has < 0 {
+			return errRumbleQuery
+		}
+		b = has != 0
+		return nil
+	})
+	return b, err
+}
@@ -0,0 +1,509 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import "strings"
+
+// Scancode (https://wiki.libsdl.org/SDL_Scancode)
+//
+// Scancodes identify the physical position of a key and do not depend on
+// the keyboard layout.
+type Scancode int32
+
+const (
+	UnknownScancode              Scancode = 0
+	AScancode                    Scancode = 4
+	BScancode                    Scancode = 5
+	CScancode                    Scancode = 6
+	DScancode                    Scancode = 7
+	EScancode                    Scancode = 8
+	FScancode                    Scancode = 9
+	GScancode                    Scancode = 10
+	HScancode                    Scancode = 11
+	IScancode                    Scancode = 12
+	JScancode                    Scancode = 13
+	KScancode                    Scancode = 14
+	LScancode                    Scancode = 15
+	MScancode                    Scancode = 16
+	NScancode                    Scancode = 17
+	OScancode                    Scancode = 18
+	PScancode                    Scancode = 19
+	QScancode                    Scancode = 20
+	RScancode                    Scancode = 21
+	SScancode                    Scancode = 22
+	TScancode                    Scancode = 23
+	UScancode                    Scancode = 24
+	VScancode                    Scancode = 25
+	WScancode                    Scancode = 26
+	XScancode                    Scancode = 27
+	YScancode                    Scancode = 28
+	ZScancode                    Scancode = 29
+	Num1Scancode                 Scancode = 30
+	Num2Scancode                 Scancode = 31
+	Num3Scancode                 Scancode = 32
+	Num4Scancode                 Scancode = 33
+	Num5Scancode                 Scancode = 34
+	Num6Scancode                 Scancode = 35
+	Num7Scancode                 Scancode = 36
+	Num8Scancode                 Scancode = 37
+	Num9Scancode                 Scancode = 38
+	Num0Scancode                 Scancode = 39
+	ReturnScancode               Scancode = 40
+	EscScancode                  Scancode = 41
+	BackSpaceScancode            Scancode = 42
+	TabScancode                  Scancode = 43
+	SpaceScancode                Scancode = 44
+	MinusScancode                Scancode = 45
+	EqualsScancode               Scancode = 46
+	LeftBracketScancode          Scancode = 47
+	RightBracketScancode         Scancode = 48
+	BackslashScancode            Scancode = 49
+	NonUSHashScancode            Scancode = 50
+	SemicolonScancode            Scancode = 51
+	ApostropheScancode           Scancode = 52
+	GraveScancode                Scancode = 53
+	CommaScancode                Scancode = 54
+	PeriodScancode               Scancode = 55
+	SlashScancode                Scancode = 56
+	CapsLockScancode             Scancode = 57
+	F1Scancode                   Scancode = 58
+	F2Scancode                   Scancode = 59
+	F3Scancode                   Scancode = 60
+	F4Scancode                   Scancode = 61
+	F5Scancode                   Scancode = 62
+	F6Scancode                   Scancode = 63
+	F7Scancode                   Scancode = 64
+	F8Scancode                   Scancode = 65
+	F9Scancode                   Scancode = 66
+	F10Scancode                  Scancode = 67
+	F11Scancode                  Scancode = 68
+	F12Scancode                  Scancode = 69
+	PrintScreenScancode          Scancode = 70
+	ScrollLockScancode           Scancode = 71
+	PauseScancode                Scancode = 72
+	InsertScancode               Scancode = 73
+	HomeScancode                 Scancode = 74
+	PageUpScancode               Scancode = 75
+	DeleteScancode               Scancode = 76
+	EndScancode                  Scancode = 77
+	PageDownScancode             Scancode = 78
+	RightScancode                Scancode = 79
+	LeftScancode                 Scancode = 80
+	DownScancode                 Scancode = 81
+	UpScancode                   Scancode = 82
+	NumLockClearScancode         Scancode = 83
+	KeypadDivideScancode         Scancode = 84
+	KeypadMultiplyScancode       Scancode = 85
+	KeypadMinusScancode          Scancode = 86
+	KeypadPlusScancode           Scancode = 87
+	KeypadEnterScancode          Scancode = 88
+	Keypad1Scancode              Scancode = 89
+	Keypad2Scancode              Scancode = 90
+	Keypad3Scancode              Scancode = 91
+	Keypad4Scancode              Scancode = 92
+	Keypad5Scancode              Scancode = 93
+	Keypad6Scancode              Scancode = 94
+	Keypad7Scancode              Scancode = 95
+	Keypad8Scancode              Scancode = 96
+	Keypad9Scancode              Scancode = 97
+	Keypad0Scancode              Scancode = 98
+	KeypadPeriodScancode         Scancode = 99
+	NonUSBackslashScancode       Scancode = 100
+	ApplicationScancode          Scancode = 101
+	PowerScancode                Scancode = 102
+	KeypadEqualsScancode         Scancode = 103
+	F13Scancode                  Scancode = 104
+	F14Scancode                  Scancode = 105
+	F15Scancode                  Scancode = 106
+	F16Scancode                  Scancode = 107
+	F17Scancode                  Scancode = 108
+	F18Scancode                  Scancode = 109
+	F19Scancode                  Scancode = 110
+	F20Scancode                  Scancode = 111
+	F21Scancode                  Scancode = 112
+	F22Scancode                  Scancode = 113
+	F23Scancode                  Scancode = 114
+	F24Scancode                  Scancode = 115
+	ExecuteScancode              Scancode = 116
+	HelpScancode                 Scancode = 117
+	MenuScancode                 Scancode = 118
+	SelectScancode               Scancode = 119
+	StopScancode                 Scancode = 120
+	AgainScancode                Scancode = 121
+	UndoScancode                 Scancode = 122
+	CutScancode                  Scancode = 123
+	CopyScancode                 Scancode = 124
+	PasteScancode                Scancode = 125
+	FindScancode                 Scancode = 126
+	MuteScancode                 Scancode = 127
+	VolumeUpScancode             Scancode = 128
+	VolumeDownScancode           Scancode = 129
+	KeypadCommaScancode          Scancode = 133
+	KeypadEqualsAS400Scancode    Scancode = 134
+	International1Scancode       Scancode = 135
+	International2Scancode       Scancode = 136
+	International3Scancode       Scancode = 137
+	International4Scancode       Scancode = 138
+	International5Scancode       Scancode = 139
+	International6Scancode       Scancode = 140
+	International7Scancode       Scancode = 141
+	International8Scancode       Scancode = 142
+	International9Scancode       Scancode = 143
+	Lang1Scancode                Scancode = 144
+	Lang2Scancode                Scancode = 145
+	Lang3Scancode                Scancode = 146
+	Lang4Scancode                Scancode = 147
+	Lang5Scancode                Scancode = 148
+	Lang6Scancode                Scancode = 149
+	Lang7Scancode                Scancode = 150
+	Lang8Scancode                Scancode = 151
+	Lang9Scancode                Scancode = 152
+	AltEraseScancode             Scancode = 153
+	SysReqScancode               Scancode = 154
+	CancelScancode               Scancode = 155
+	ClearScancode                Scancode = 156
+	PriorScancode                Scancode = 157
+	Return2Scancode              Scancode = 158
+	SeparatorScancode            Scancode = 159
+	OutScancode                  Scancode = 160
+	OperScancode                 Scancode = 161
+	ClearAgainScancode           Scancode = 162
+	CrSelScancode                Scancode = 163
+	ExSelScancode                Scancode = 164
+	Keypad00Scancode             Scancode = 176
+	Keypad000Scancode            Scancode = 177
+	ThousandsSeparatorScancode   Scancode = 178
+	DecimalSeparatorScancode     Scancode = 179
+	CurrencyUnitScancode         Scancode = 180
+	CurrencySubUnitScancode      Scancode = 181
+	KeypadLeftParenScancode      Scancode = 182
+	KeypadRightParenScancode     Scancode = 183
+	KeypadLeftBraceScancode      Scancode = 184
+	KeypadRightBraceScancode     Scancode = 185
+	KeypadTabScancode            Scancode = 186
+	KeypadBackSpaceScancode      Scancode = 187
+	KeypadAScancode              Scancode = 188
+	KeypadBScancode              Scancode = 189
+	KeypadCScancode              Scancode = 190
+	KeypadDScancode              Scancode = 191
+	KeypadEScancode              Scancode = 192
+	KeypadFScancode              Scancode = 193
+	KeypadXorScancode            Scancode = 194
+	KeypadPowerScancode          Scancode = 195
+	KeypadPercentScancode        Scancode = 196
+	KeypadLessScancode           Scancode = 197
+	KeypadGreaterScancode        Scancode = 198
+	KeypadAmpersandScancode      Scancode = 199
+	KeypadDblAmpersandScancode   Scancode = 200
+	KeypadVerticalBarScancode    Scancode = 201
+	KeypadDblVerticalBarScancode Scancode = 202
+	KeypadColonScancode          Scancode = 203
+	KeypadHashScancode           Scancode = 204
+	KeypadSpaceScancode          Scancode = 205
+	KeypadAtScancode             Scancode = 206
+	KeypadExclaimScancode        Scancode = 207
+	KeypadMemStoreScancode       Scancode = 208
+	KeypadMemRecallScancode      Scancode = 209
+	KeypadMemClearScancode       Scancode = 210
+	KeypadMemAddScancode         Scancode = 211
+	KeypadMemSubtractScancode    Scancode = 212
+	KeypadMemMultiplyScancode    Scancode = 213
+	KeypadMemDivideScancode      Scancode = 214
+	KeypadPlusMinusScancode      Scancode = 215
+	KeypadClearScancode          Scancode = 216
+	KeypadClearEntryScancode     Scancode = 217
+	KeypadBinaryScancode         Scancode = 218
+	KeypadOctalScancode          Scancode = 219
+	KeypadDecimalScancode        Scancode = 220
+	KeypadHexadecimalScancode    Scancode = 221
+	LeftCtrlScancode             Scancode = 224
+	LeftShiftScancode            Scancode = 225
+	LeftAltScancode              Scancode = 226
+	LeftGuiScancode              Scancode = 227
+	RightCtrlScancode            Scancode = 228
+	RightShiftScancode           Scancode = 229
+	RightAltScancode             Scancode = 230
+	RightGuiScancode             Scancode = 231
+	ModeScancode                 Scancode = 257
+	AudioNextScancode            Scancode = 258
+	AudioPrevScancode            Scancode = 259
+	AudioStopScancode            Scancode = 260
+	AudioPlayScancode            Scancode = 261
+	AudioMuteScancode            Scancode = 262
+	MediaSelectScancode          Scancode = 263
+	WWWScancode                  Scancode = 264
+	MailScancode                 Scancode = 265
+	CalculatorScancode           Scancode = 266
+	ComputerScancode             Scancode = 267
+	ACSearchScancode             Scancode = 268
+	ACHomeScancode               Scancode = 269
+	ACBackScancode               Scancode = 270
+	ACForwardScancode            Scancode = 271
+	ACStopScancode               Scancode = 272
+	ACRefreshScancode            Scancode = 273
+	ACBookmarksScancode          Scancode = 274
+	BrightnessDownScancode       Scancode = 275
+	BrightnessUpScancode         Scancode = 276
+	DisplaySwitchScancode        Scancode = 277
+	KbdIllumToggleScancode       Scancode = 278
+	KbdIllumDownScancode         Scancode = 279
+	KbdIllumUpScancode           Scancode = 280
+	EjectScancode                Scancode = 281
+	SleepScancode                Scancode = 282
+	App1Scancode                 Scancode = 283
+	App2Scancode                 Scancode = 284
+	AudioRewindScancode          Scancode = 285
+	AudioFastForwardScancode     Scancode = 286
+)
+
+const numScancodes = 512
+
+var scancodeNames = [numScancodes]string{
+	AScancode:                    "A",
+	BScancode:                    "B",
+	CScancode:                    "C",
+	DScancode:                    "D",
+	EScancode:                    "E",
+	FScancode:                    "F",
+	GScancode:                    "G",
+	HScancode:                    "H",
+	IScancode:                    "I",
+	JScancode:                    "J",
+	KScancode:                    "K",
+	LScancode:                    "L",
+	MScancode:                    "M",
+	NScancode:                    "N",
+	OScancode:                    "O",
+	PScancode:                    "P",
+	QScancode:                    "Q",
+	RScancode:                    "R",
+	SScancode:                    "S",
+	TScancode:                    "T",
+	UScancode:                    "U",
+	VScancode:                    "V",
+	WScancode:                    "W",
+	XScancode:                    "X",
+	YScancode:                    "Y",
+	ZScancode:                    "Z",
+	Num1Scancode:                 "1",
+	Num2Scancode:                 "2",
+	Num3Scancode:                 "3",
+	Num4Scancode:                 "4",
+	Num5Scancode:                 "5",
+	Num6Scancode:                 "6",
+	Num7Scancode:                 "7",
+	Num8Scancode:                 "8",
+	Num9Scancode:                 "9",
+	Num0Scancode:                 "0",
+	ReturnScancode:               "Return",
+	EscScancode:                  "Escape",
+	BackSpaceScancode:            "Backspace",
+	TabScancode:                  "Tab",
+	SpaceScancode:                "Space",
+	MinusScancode:                "-",
+	EqualsScancode:               "=",
+	LeftBracketScancode:          "[",
+	RightBracketScancode:         "]",
+	BackslashScancode:            "\\",
+	NonUSHashScancode:            "#",
+	SemicolonScancode:            ";",
+	ApostropheScancode:           "'",
+	GraveScancode:                "`",
+	CommaScancode:                ",",
+	PeriodScancode:               ".",
+	SlashScancode:                "/",
+	CapsLockScancode:             "CapsLock",
+	F1Scancode:                   "F1",
+	F2Scancode:                   "F2",
+	F3Scancode:                   "F3",
+	F4Scancode:                   "F4",
+	F5Scancode:                   "F5",
+	F6Scancode:                   "F6",
+	F7Scancode:                   "F7",
+	F8Scancode:                   "F8",
+	F9Scancode:                   "F9",
+	F10Scancode:                  "F10",
+	F11Scancode:                  "F11",
+	F12Scancode:                  "F12",
+	PrintScreenScancode:          "PrintScreen",
+	ScrollLockScancode:           "ScrollLock",
+	PauseScancode:                "Pause",
+	InsertScancode:               "Insert",
+	HomeScancode:                 "Home",
+	PageUpScancode:               "PageUp",
+	DeleteScancode:               "Delete",
+	EndScancode:                  "End",
+	PageDownScancode:             "PageDown",
+	RightScancode:                "Right",
+	LeftScancode:                 "Left",
+	DownScancode:                 "Down",
+	UpScancode:                   "Up",
+	NumLockClearScancode:         "Numlock",
+	KeypadDivideScancode:         "Keypad /",
+	KeypadMultiplyScancode:       "Keypad *",
+	KeypadMinusScancode:          "Keypad -",
+	KeypadPlusScancode:           "Keypad +",
+	KeypadEnterScancode:          "Keypad Enter",
+	Keypad1Scancode:              "Keypad 1",
+	Keypad2Scancode:              "Keypad 2",
+	Keypad3Scancode:              "Keypad 3",
+	Keypad4Scancode:              "Keypad 4",
+	Keypad5Scancode:              "Keypad 5",
+	Keypad6Scancode:              "Keypad 6",
+	Keypad7Scancode:              "Keypad 7",
+	Keypad8Scancode:              "Keypad 8",
+	Keypad9Scancode:              "Keypad 9",
+	Keypad0Scancode:              "Keypad 0",
+	KeypadPeriodScancode:         "Keypad .",
+	ApplicationScancode:          "Application",
+	PowerScancode:                "Power",
+	KeypadEqualsScancode:         "Keypad =",
+	F13Scancode:                  "F13",
+	F14Scancode:                  "F14",
+	F15Scancode:                  "F15",
+	F16Scancode:                  "F16",
+	F17Scancode:                  "F17",
+	F18Scancode:                  "F18",
+	F19Scancode:                  "F19",
+	F20Scancode:                  "F20",
+	F21Scancode:                  "F21",
+	F22Scancode:                  "F22",
+	F23Scancode:                  "F23",
+	F24Scancode:                  "F24",
+	ExecuteScancode:              "Execute",
+	HelpScancode:                 "Help",
+	MenuScancode:                 "Menu",
+	SelectScancode:               "Select",
+	StopScancode:                 "Stop",
+	AgainScancode:                "Again",
+	UndoScancode:                 "Undo",
+	CutScancode:                  "Cut",
+	CopyScancode:                 "Copy",
+	PasteScancode:                "Paste",
+	FindScancode:                 "Find",
+	MuteScancode:                 "Mute",
+	VolumeUpScancode:             "VolumeUp",
+	VolumeDownScancode:           "VolumeDown",
+	KeypadCommaScancode:          "Keypad ,",
+	KeypadEqualsAS400Scancode:    "Keypad = (AS400)",
+	AltEraseScancode:             "AltErase",
+	SysReqScancode:               "SysReq",
+	CancelScancode:               "Cancel",
+	ClearScancode:                "Clear",
+	PriorScancode:                "Prior",
+	Return2Scancode:              "Return",
+	SeparatorScancode:            "Separator",
+	OutScancode:                  "Out",
+	OperScancode:                 "Oper",
+	ClearAgainScancode:           "Clear / Again",
+	CrSelScancode:                "CrSel",
+	ExSelScancode:                "ExSel",
+	Keypad00Scancode:             "Keypad 00",
+	Keypad000Scancode:            "Keypad 000",
+	ThousandsSeparatorScancode:   "ThousandsSeparator",
+	DecimalSeparatorScancode:     "DecimalSeparator",
+	CurrencyUnitScancode:         "CurrencyUnit",
+	CurrencySubUnitScancode:      "CurrencySubUnit",
+	KeypadLeftParenScancode:      "Keypad (",
+	KeypadRightParenScancode:     "Keypad )",
+	KeypadLeftBraceScancode:      "Keypad {",
+	KeypadRightBraceScancode:     "Keypad }",
+	KeypadTabScancode:            "Keypad Tab",
+	KeypadBackSpaceScancode:      "Keypad Backspace",
+	KeypadAScancode:              "Keypad A",
+	KeypadBScancode:              "Keypad B",
+	KeypadCScancode:              "Keypad C",
+	KeypadDScancode:              "Keypad D",
+	KeypadEScancode:              "Keypad E",
+	KeypadFScancode:              "Keypad F",
+	KeypadXorScancode:            "Keypad XOR",
+	KeypadPowerScancode:          "Keypad ^",
+	KeypadPercentScancode:        "Keypad %",
+	KeypadLessScancode:           "Keypad <",
+	KeypadGreaterScancode:        "Keypad >",
+	KeypadAmpersandScancode:      "Keypad &",
+	KeypadDblAmpersandScancode:   "Keypad &&",
+	KeypadVerticalBarScancode:    "Keypad |",
+	KeypadDblVerticalBarScancode: "Keypad ||",
+	KeypadColonScancode:          "Keypad :",
+	KeypadHashScancode:           "Keypad #",
+	KeypadSpaceScancode:          "Keypad Space",
+	KeypadAtScancode:             "Keypad @",
+	KeypadExclaimScancode:        "Keypad !",
+	KeypadMemStoreScancode:       "Keypad MemStore",
+	KeypadMemRecallScancode:      "Keypad MemRecall",
+	KeypadMemClearScancode:       "Keypad MemClear",
+	KeypadMemAddScancode:         "Keypad MemAdd",
+	KeypadMemSubtractScancode:    "Keypad MemSubtract",
+	KeypadMemMultiplyScancode:    "Keypad MemMultiply",
+	KeypadMemDivideScancode:      "Keypad MemDivide",
+	KeypadPlusMinusScancode:      "Keypad +/-",
+	KeypadClearScancode:          "Keypad Clear",
+	KeypadClearEntryScancode:     "Keypad ClearEntry",
+	KeypadBinaryScancode:         "Keypad Binary",
+	KeypadOctalScancode:          "Keypad Octal",
+	KeypadDecimalScancode:        "Keypad Decimal",
+	KeypadHexadecimalScancode:    "Keypad Hexadecimal",
+	LeftCtrlScancode:             "Left Ctrl",
+	LeftShiftScancode:            "Left Shift",
+	LeftAltScancode:              "Left Alt",
+	LeftGuiScancode:              "Left GUI",
+	RightCtrlScancode:            "Right Ctrl",
+	RightShiftScancode:           "Right Shift",
+	RightAltScancode:             "Right Alt",
+	RightGuiScancode:             "Right GUI",
+	ModeScancode:                 "ModeSwitch",
+	AudioNextScancode:            "AudioNext",
+	AudioPrevScancode:            "AudioPrev",
+	AudioStopScancode:            "AudioStop",
+	AudioPlayScancode:            "AudioPlay",
+	AudioMuteScancode:            "AudioMute",
+	MediaSelectScancode:          "MediaSelect",
+	WWWScancode:                  "WWW",
+	MailScancode:                 "Mail",
+	CalculatorScancode:           "Calculator",
+	ComputerScancode:             "Computer",
+	ACSearchScancode:             "AC Search",
+	ACHomeScancode:               "AC Home",
+	ACBackScancode:               "AC Back",
+	ACForwardScancode:            "AC Forward",
+	ACStopScancode:               "AC Stop",
+	ACRefreshScancode:            "AC Refresh",
+	ACBookmarksScancode:          "AC Bookmarks",
+	BrightnessDownScancode:       "BrightnessDown",
+	BrightnessUpScancode:         "BrightnessUp",
+	DisplaySwitchScancode:        "DisplaySwitch",
+	KbdIllumToggleScancode:       "KBDIllumToggle",
+	KbdIllumDownScancode:         "KBDIllumDown",
+	KbdIllumUpScancode:           "KBDIllumUp",
+	EjectScancode:                "Eject",
+	SleepScancode:                "Sleep",
+	App1Scancode:                 "App1",
+	App2Scancode:                 "App2",
+	AudioRewindScancode:          "AudioRewind",
+	AudioFastForwardScancode:     "AudioFastForward",
+}
+
+// Name returns the same name as SDL_GetScancodeName.
+func (s Scancode) Name() string {
+	if s < 0 || s >= numScancodes {
+		return ""
+	}
+	return scancodeNames[s]
+}
+
+// ScancodeFromName is the inverse of Scancode.Name, the comparison is case
+// insensitive.
+func ScancodeFromName(name string) Scancode {
+	if name == "" {
+		return UnknownScancode
+	}
+	for s, n := range scancodeNames {
+		if strings.EqualFold(n, name) {
+			return Scancode(s)
+		}
+	}
+	return UnknownScancode
+}
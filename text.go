@@ -0,0 +1,38 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"image"
+	"unsafe"
+)
+
+var textInputRect sdlRect
+
+// StartTextInput enables TextInputEvent and TextEditingEvent events, and
+// shows the on-screen keyboard or IME if the platform has one.
+func StartTextInput() error {
+	return sendCommand(false, func() error {
+		sdlStartTextInput()
+		return nil
+	})
+}
+
+func StopTextInput() error {
+	return sendCommand(false, func() error {
+		sdlStopTextInput()
+		return nil
+	})
+}
+
+// SetTextInputRect hints where text is being entered, in window coordinates,
+// so that an IME candidate list can be placed next to it.
+func SetTextInputRect(r image.Rectangle) error {
+	return sendCommand(false, func() error {
+		textInputRect = newSDLRect(r)
+		sdlSetTextInputRect(unsafe.Pointer(&textInputRect))
+		return nil
+	})
+}
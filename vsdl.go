@@ -7,13 +7,11 @@
 package vsdl
 
 import (
-	"errors"
 	"fmt"
 	"image"
 	"io/ioutil"
 	logpkg "log"
 	"runtime"
-	"unsafe"
 	"sync"
 )
 
@@ -37,6 +35,9 @@ type Config func() error
 
 func ConfigWithLibrary(p string) Config {
 	return func() error {
+		if windowConfig.newWindow {
+			return errGlobalConfig
+		}
 		libraryName = p
 		return nil
 	}
@@ -44,6 +45,9 @@ func ConfigWithLibrary(p string) Config {
 
 func ConfigWithLogger(l *logpkg.Logger) Config {
 	return func() error {
+		if windowConfig.newWindow {
+			return errGlobalConfig
+		}
 		log = l
 		return nil
 	}
@@ -51,14 +55,28 @@ func ConfigWithLogger(l *logpkg.Logger) Config {
 
 func ConfigWithRenderer(size, logical image.Point) Config {
 	return func() error {
-		windowSize = size
-		logicalSize = logical
+		windowConfig.size = size
+		windowConfig.logicalSize = logical
+		return nil
+	}
+}
+
+// ConfigWithResizable makes the window resizable by the user. Without a logical
+// size the back-buffer follows the window size, see BackBufferSize.
+func ConfigWithResizable(b bool) Config {
+	return func() error {
+		windowConfig.resizable = b
 		return nil
 	}
 }
 
 var log = logpkg.New(ioutil.Discard, "", logpkg.LstdFlags)
 
+var (
+	libraryHandle                libHandle
+	libraryName, removeDirectory string
+)
+
 var sdlExpectedVersion = [2]byte{2, 0}
 
 type command struct {
@@ -71,10 +89,7 @@ var (
 	commandChan chan command
 )
 
-var (
-	windowSize, logicalSize   image.Point
-	window, renderer, texture uintptr
-)
+var initFlags uint32
 
 func init() {
 	runtime.LockOSThread()
@@ -89,27 +104,23 @@ func sendCommand(async bool, f func() error) error {
 }
 
 func ToggleFullscreen() (bool, error) {
-	res := make(chan bool, 1)
-	err := sendCommand(false, func() error {
-		b, err := sdlToggleFullscreen(window)
-		res <- b
-		return err
-	})
-	return <-res, err
+	if defaultWindow == nil {
+		return false, errWindowClosed
+	}
+	return defaultWindow.ToggleFullscreen()
 }
 
 func Initialize(f func() error, configs ...Config) error {
-	windowSize = image.Point{640, 480}
-	logicalSize = image.Point{}
-	errorChan = make(chan error)
-	commandChan = make(chan command)
+	initFlags = 0
 
-	for _, cfg := range configs {
-		if err := cfg(); err != nil {
-			return newError(err, "configuration error")
-		}
+	w, err := configureWindow(configs, false)
+	if err != nil {
+		return err
 	}
 
+	errorChan = make(chan error)
+	commandChan = make(chan command)
+
 	if err := initProcs(); err != nil {
 		return err
 	}
@@ -122,34 +133,17 @@ func Initialize(f func() error, configs ...Config) error {
 
 	const sdlInitVideoFlag uint32 = 0x00000020
 
-	if sdlInit(sdlInitVideoFlag) {
+	if sdlInit(sdlInitVideoFlag | initFlags) {
 		return sdlToGoError()
 	}
 	defer sdlQuit()
+	defer stopQueueWatches()
 
-	windowPtr := uintptr(unsafe.Pointer(&window))
-	rendererPtr := uintptr(unsafe.Pointer(&renderer))
-
-	if sdlCreateWindowAndRenderer(windowSize, windowPtr, rendererPtr) {
-		return sdlToGoError()
-	}
-	defer sdlDestroyRendererAndWindow(window, renderer)
-
-	if logicalSize.X != 0 {
-		if sdlRenderSetLogicalSize(renderer, logicalSize) {
-			return sdlToGoError()
-		}
-	}
-
-	backBufferSize := windowSize
-	if logicalSize.X != 0 {
-		backBufferSize = logicalSize
-	}
-
-	if texture = sdlCreateTexture(renderer, backBufferSize); texture == 0 {
-		return sdlToGoError()
+	if err := w.create(); err != nil {
+		return err
 	}
-	defer sdlDestroyTexture(texture)
+	defaultWindow = w
+	defer destroyWindows()
 
 	go func() {
 		err := f()
@@ -157,14 +151,24 @@ func Initialize(f func() error, configs ...Config) error {
 		errorChan <- err
 	}()
 
-	for c := range commandChan {
-		err := c.f()
-		if !c.a {
-			errorChan <- err
+	for {
+		select {
+		case <-uploadSignal:
+			runUploads()
+		case c, ok := <-commandChan:
+			runUploads()
+			if !ok {
+				return <-errorChan
+			}
+
+			err := c.f()
+			if !c.a {
+				errorChan <- err
+			} else if err != nil {
+				log.Println(err)
+			}
 		}
 	}
-
-	return <-errorChan
 }
 
 func Events() <-chan Event {
@@ -188,48 +192,98 @@ func Events() <-chan Event {
 }
 
 func Present(img image.Image) (*sync.WaitGroup, error) {
-	imgSize := img.Bounds().Size()
-	backBufferSize := windowSize
-	wg := new(sync.WaitGroup)
+	if defaultWindow == nil {
+		return new(sync.WaitGroup), errWindowClosed
+	}
+	return defaultWindow.Present(img)
+}
 
-	if logicalSize.X != 0 {
-		backBufferSize = logicalSize
+// PresentRegions works like Present but only uploads the parts of img that
+// intersects with rects. The rectangles are given in the coordinate space of img.
+func PresentRegions(img image.Image, rects []image.Rectangle) (*sync.WaitGroup, error) {
+	if defaultWindow == nil {
+		return new(sync.WaitGroup), errWindowClosed
 	}
+	return defaultWindow.PresentRegions(img, rects)
+}
 
-	if imgSize != backBufferSize {
-		return wg, errors.New("image is not the same size as the back-buffer")
+// LockFrame returns an image that aliases the memory of the back-buffer
+// texture, allowing the frame to be drawn without an extra copy. The initial
+// content of the image is undefined so every pixel has to be written before
+// calling UnlockAndPresent. The image must not be used after that call.
+func LockFrame() (*image.RGBA, error) {
+	if defaultWindow == nil {
+		return nil, errWindowClosed
 	}
+	return defaultWindow.LockFrame()
+}
 
-	rgba, ok := img.(*image.RGBA)
-	if !ok {
-		return wg, errors.New("invalid image format")
+// UnlockAndPresent submits the frame returned by LockFrame.
+func UnlockAndPresent() error {
+	if defaultWindow == nil {
+		return errWindowClosed
 	}
-	
-	wg.Add(1)
-	return wg, sendCommand(false, func() error {
-		if sdlUpdateTexture(texture, uintptr(unsafe.Pointer(&rgba.Pix[0])), uintptr(rgba.Stride)) {
-			wg.Done()
-			return sdlToGoError()
-		}
-		wg.Done()
+	return defaultWindow.UnlockAndPresent()
+}
 
-		if sdlRenderCopy(renderer, texture) {
-			return sdlToGoError()
-		}
+// sdlRect (https://wiki.libsdl.org/SDL_Rect)
+type sdlRect struct {
+	X, Y, W, H int32
+}
 
-		sdlRenderPresent(renderer)
-		return nil
-	})
+// sdlDisplayMode (https://wiki.libsdl.org/SDL_DisplayMode)
+type sdlDisplayMode struct {
+	Format      uint32
+	W, H        int32
+	RefreshRate int32
+	DriverData  uintptr
+}
+
+var displayMode, closestDisplayMode sdlDisplayMode
+
+const sdl_WINDOW_FULLSCREEN uint32 = 0x00000001
+const sdl_WINDOW_FULLSCREEN_DESKTOP uint32 = sdl_WINDOW_FULLSCREEN | 0x00001000
+
+const (
+	sdl_WINDOW_BORDERLESS    uint32 = 0x00000010
+	sdl_WINDOW_RESIZABLE     uint32 = 0x00000020
+	sdl_WINDOW_MINIMIZED     uint32 = 0x00000040
+	sdl_WINDOW_MAXIMIZED     uint32 = 0x00000080
+	sdl_WINDOW_ALWAYS_ON_TOP uint32 = 0x00008000
+)
+
+const sdl_WINDOWPOS_UNDEFINED = 0x1FFF0000
+
+const sdl_RENDERER_PRESENTVSYNC uint32 = 0x00000004
+
+func newSDLRect(r image.Rectangle) sdlRect {
+	return sdlRect{int32(r.Min.X), int32(r.Min.Y), int32(r.Dx()), int32(r.Dy())}
 }
 
 func definePixelFormat(ty, order, layout, bits, bytes uint32) uint32 {
 	return (1 << 28) | (ty << 24) | (order << 20) | (layout << 16) | (bits << 8) | (bytes << 0)
 }
 
+func definePixelFourCC(a, b, c, d byte) uint32 {
+	return uint32(a) | uint32(b)<<8 | uint32(c)<<16 | uint32(d)<<24
+}
+
 const (
+	pixelTypePacked16 = 5
 	pixelTypePacked32 = 6
+	packedOrderXRGB   = 1
+	packedOrderARGB   = 3
 	packedOrderABGR   = 7
+	packedLayout565   = 5
 	packedLayout8888  = 6
 )
 
-var pixelFormatABGR8888 = definePixelFormat(pixelTypePacked32, packedOrderABGR, packedLayout8888, 32, 4)
+var (
+	pixelFormatABGR8888 = definePixelFormat(pixelTypePacked32, packedOrderABGR, packedLayout8888, 32, 4)
+	pixelFormatARGB8888 = definePixelFormat(pixelTypePacked32, packedOrderARGB, packedLayout8888, 32, 4)
+	pixelFormatRGB888   = definePixelFormat(pixelTypePacked32, packedOrderXRGB, packedLayout8888, 24, 4)
+	pixelFormatRGB565   = definePixelFormat(pixelTypePacked16, packedOrderXRGB, packedLayout565, 16, 2)
+	pixelFormatYV12     = definePixelFourCC('Y', 'V', '1', '2')
+	pixelFormatIYUV     = definePixelFourCC('I', 'Y', 'U', 'V')
+	pixelFormatNV12     = definePixelFourCC('N', 'V', '1', '2')
+)
@@ -0,0 +1,1073 @@
+// +build headless
+
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+// The headless backend is selected with the "headless" build tag. It is
+// implemented in pure Go and does not require SDL or a video device, presented
+// frames are captured in memory and events can be injected with InjectEvent.
+
+package vsdl
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+type libHandle = uintptr
+
+// maxCapturedFrames limits the number of frames kept by the headless backend.
+const maxCapturedFrames = 64
+
+// maxCapturedAudio limits the number of audio bytes kept by the headless backend.
+const maxCapturedAudio = 1 << 20
+
+type headlessWindow struct {
+	size     image.Point
+	flags    uint32
+	title    string
+	icon     *image.RGBA
+	position image.Point
+	opacity  float32
+	mode     sdlDisplayMode
+	windowed image.Point
+}
+
+type headlessRenderer struct {
+	window      uintptr
+	logicalSize image.Point
+	texture     uintptr
+}
+
+type headlessTexture struct {
+	format uint32
+	img    image.Image
+	locked bool
+}
+
+type headlessAudioDevice struct {
+	spec   sdlAudioSpec
+	paused bool
+	queue  []byte
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+var (
+	headlessLock     sync.Mutex
+	headlessHandles  = map[uintptr]interface{}{}
+	headlessNextID   uintptr
+	headlessError    string
+	headlessEvents   []sdlEvent
+	headlessCaptured []*image.RGBA
+	headlessAudio    []byte
+
+	headlessKeyboard [numScancodes]uint8
+	headlessModState uint16
+	headlessMouse    image.Point
+	headlessButtons  uint32
+)
+
+// InjectEvent queues an event that is returned by Events as if it had been
+// generated by SDL. The event type is derived from the Go type of ev, an error
+// is returned for types that are not defined by this package.
+func InjectEvent(ev Event) error {
+	var (
+		raw  sdlEvent
+		ty   uint32
+		ptr  unsafe.Pointer
+		size uintptr
+	)
+
+	switch t := ev.(type) {
+	case *QuitEvent:
+		ty, ptr, size = sdlQuitEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *WindowEvent:
+		ty, ptr, size = sdlWindowEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *KeyDownEvent:
+		ty, ptr, size = sdlKeyDownEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *KeyUpEvent:
+		ty, ptr, size = sdlKeyUpEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *TextEditingEvent:
+		ty, ptr, size = sdlTextEditingEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *TextInputEvent:
+		ty, ptr, size = sdlTextInputEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *MouseMotionEvent:
+		ty, ptr, size = sdlMouseMotionEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *MouseButtonEvent:
+		ty, ptr, size = sdlMouseButtonDownEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+		if t.State == 0 {
+			ty = sdlMouseButtonUpEventType
+		}
+	case *MouseWheelEvent:
+		ty, ptr, size = sdlMouseWheelEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *ControllerAxisEvent:
+		ty, ptr, size = sdlControllerAxisMotionEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *ControllerButtonEvent:
+		ty, ptr, size = sdlControllerButtonDownEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+		if t.State == 0 {
+			ty = sdlControllerButtonUpEventType
+		}
+	case *ControllerDeviceAddedEvent:
+		ty, ptr, size = sdlControllerDeviceAddedEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *ControllerDeviceRemovedEvent:
+		ty, ptr, size = sdlControllerDeviceRemovedEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *ControllerDeviceRemappedEvent:
+		ty, ptr, size = sdlControllerDeviceRemappedEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *AudioDeviceAddedEvent:
+		ty, ptr, size = sdlAudioDeviceAddedEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *AudioDeviceRemovedEvent:
+		ty, ptr, size = sdlAudioDeviceRemovedEventType, unsafe.Pointer(t), unsafe.Sizeof(*t)
+	case *AudioUnderrunEvent:
+		// Underrun events are generated by vsdl and never pass through the
+		// SDL event queue.
+		postEvent(newAudioUnderrunEvent(t.Which))
+		return nil
+	default:
+		return errors.New("unsupported event type")
+	}
+
+	copy(raw[:], (*[sdlEventMaxSize]byte)(ptr)[:size])
+	*(*uint32)(unsafe.Pointer(&raw[0])) = ty
+
+	headlessLock.Lock()
+	headlessEvents = append(headlessEvents, raw)
+	headlessLock.Unlock()
+	return nil
+}
+
+// CapturedFrames returns the frames presented since the last call, oldest
+// first. Only the last 64 frames are kept.
+func CapturedFrames() []*image.RGBA {
+	headlessLock.Lock()
+	defer headlessLock.Unlock()
+
+	frames := headlessCaptured
+	headlessCaptured = nil
+	return frames
+}
+
+// CapturedAudio returns the samples played since the last call. Only the last
+// megabyte is kept.
+func CapturedAudio() []byte {
+	headlessLock.Lock()
+	defer headlessLock.Unlock()
+
+	audio := headlessAudio
+	headlessAudio = nil
+	return audio
+}
+
+func headlessHandle(obj interface{}) uintptr {
+	headlessLock.Lock()
+	defer headlessLock.Unlock()
+
+	headlessNextID++
+	headlessHandles[headlessNextID] = obj
+	return headlessNextID
+}
+
+func headlessObject(h uintptr) interface{} {
+	headlessLock.Lock()
+	defer headlessLock.Unlock()
+	return headlessHandles[h]
+}
+
+func headlessRelease(h uintptr) {
+	headlessLock.Lock()
+	delete(headlessHandles, h)
+	headlessLock.Unlock()
+}
+
+func headlessSetError(s string) bool {
+	headlessError = s
+	return true
+}
+
+func headlessMemory(p unsafe.Pointer, n int) []byte {
+	return (*[1 << 30]byte)(p)[:n:n]
+}
+
+func initProcs() error {
+	return nil
+}
+
+func loadLibrary(name string) (libHandle, error) {
+	return 0, nil
+}
+
+func unloadLibrary() {
+	libraryName = ""
+	libraryHandle = 0
+}
+
+func getProc(name string) (uintptr, error) {
+	return 0, nil
+}
+
+func sdlToGoError() error {
+	if headlessError == "" {
+		return nil
+	}
+	err := errors.New(headlessError)
+	headlessError = ""
+	return err
+}
+
+func sdlGetVersion() [3]byte {
+	return [3]byte{2, 0, 8}
+}
+
+func sdlInit(flags uint32) bool {
+	headlessLock.Lock()
+	headlessCaptured = nil
+	headlessLock.Unlock()
+	return false
+}
+
+func sdlQuit() {
+	headlessLock.Lock()
+	handles := headlessHandles
+	headlessHandles = map[uintptr]interface{}{}
+	headlessEvents = nil
+	headlessLock.Unlock()
+
+	for _, obj := range handles {
+		if d, ok := obj.(*headlessAudioDevice); ok {
+			d.close()
+		}
+	}
+}
+
+func sdlCreateWindowAndRenderer(windowSize image.Point, display int, windowFlags, rendererFlags uint32, windowPtr, rendererPtr unsafe.Pointer) bool {
+	win := &headlessWindow{size: windowSize, flags: windowFlags, opacity: 1, windowed: windowSize}
+	if windowFlags&sdl_WINDOW_FULLSCREEN != 0 {
+		win.size = win.fullscreenSize(windowFlags)
+	}
+
+	w := headlessHandle(win)
+	r := headlessHandle(&headlessRenderer{window: w})
+
+	*(*uintptr)(windowPtr) = w
+	*(*uintptr)(rendererPtr) = r
+	return false
+}
+
+func sdlDestroyRendererAndWindow(window, renderer uintptr) {
+	headlessRelease(renderer)
+	headlessRelease(window)
+}
+
+func sdlRenderSetLogicalSize(renderer uintptr, logicalSize image.Point) bool {
+	r, ok := headlessObject(renderer).(*headlessRenderer)
+	if !ok {
+		return headlessSetError("invalid renderer")
+	}
+	r.logicalSize = logicalSize
+	return false
+}
+
+func sdlGetWindowID(window uintptr) uint32 {
+	return uint32(window)
+}
+
+func headlessGetWindow(window uintptr) *headlessWindow {
+	if w, ok := headlessObject(window).(*headlessWindow); ok {
+		return w
+	}
+	return &headlessWindow{}
+}
+
+func sdlGetWindowFlags(window uintptr) uint32 {
+	return headlessGetWindow(window).flags
+}
+
+func sdlSetWindowTitle(window uintptr, title string) {
+	headlessGetWindow(window).title = title
+}
+
+func sdlGetWindowTitle(window uintptr) string {
+	return headlessGetWindow(window).title
+}
+
+func sdlSetWindowIcon(window uintptr, icon *image.RGBA) bool {
+	w, ok := headlessObject(window).(*headlessWindow)
+	if !ok {
+		return headlessSetError("invalid window")
+	}
+
+	w.icon = image.NewRGBA(icon.Rect)
+	copy(w.icon.Pix, icon.Pix)
+	return false
+}
+
+func sdlSetWindowPosition(window uintptr, pos image.Point) {
+	headlessGetWindow(window).position = pos
+}
+
+func sdlGetWindowPosition(window uintptr, x, y unsafe.Pointer) {
+	w := headlessGetWindow(window)
+	*(*int32)(x) = int32(w.position.X)
+	*(*int32)(y) = int32(w.position.Y)
+}
+
+func sdlSetWindowSize(window uintptr, size image.Point) {
+	if w := headlessGetWindow(window); w.size != size {
+		w.size = size
+		InjectEvent(&WindowEvent{WindowID: uint32(window), Event: WindowSizeChanged, Data1: int32(size.X), Data2: int32(size.Y)})
+	}
+}
+
+func sdlGetWindowSize(window uintptr, w, h unsafe.Pointer) {
+	win := headlessGetWindow(window)
+	*(*int32)(w) = int32(win.size.X)
+	*(*int32)(h) = int32(win.size.Y)
+}
+
+func sdlMinimizeWindow(window uintptr) {
+	w := headlessGetWindow(window)
+	w.flags = w.flags&^sdl_WINDOW_MAXIMIZED | sdl_WINDOW_MINIMIZED
+}
+
+func sdlMaximizeWindow(window uintptr) {
+	w := headlessGetWindow(window)
+	w.flags = w.flags&^sdl_WINDOW_MINIMIZED | sdl_WINDOW_MAXIMIZED
+}
+
+func sdlRestoreWindow(window uintptr) {
+	headlessGetWindow(window).flags &^= sdl_WINDOW_MINIMIZED | sdl_WINDOW_MAXIMIZED
+}
+
+func sdlRaiseWindow(window uintptr) {
+}
+
+func sdlSetWindowBordered(window uintptr, b bool) {
+	w := headlessGetWindow(window)
+	if b {
+		w.flags &^= sdl_WINDOW_BORDERLESS
+	} else {
+		w.flags |= sdl_WINDOW_BORDERLESS
+	}
+}
+
+func sdlSetWindowAlwaysOnTop(window uintptr, b bool) bool {
+	w := headlessGetWindow(window)
+	if b {
+		w.flags |= sdl_WINDOW_ALWAYS_ON_TOP
+	} else {
+		w.flags &^= sdl_WINDOW_ALWAYS_ON_TOP
+	}
+	return false
+}
+
+func sdlSetWindowOpacity(window uintptr, opacity float32) bool {
+	headlessGetWindow(window).opacity = opacity
+	return false
+}
+
+func sdlGetWindowOpacity(window uintptr, opacity unsafe.Pointer) bool {
+	*(*float32)(opacity) = headlessGetWindow(window).opacity
+	return false
+}
+
+func sdlGetWindowDisplayMode(window uintptr, mode unsafe.Pointer) bool {
+	w, ok := headlessObject(window).(*headlessWindow)
+	if !ok {
+		return headlessSetError("invalid window")
+	}
+
+	*(*sdlDisplayMode)(mode) = headlessDisplayModes[0]
+	if w.mode.W != 0 {
+		*(*sdlDisplayMode)(mode) = w.mode
+	}
+	return false
+}
+
+func sdlSetWindowDisplayMode(window uintptr, mode unsafe.Pointer) bool {
+	w, ok := headlessObject(window).(*headlessWindow)
+	if !ok {
+		return headlessSetError("invalid window")
+	}
+
+	w.mode = sdlDisplayMode{}
+	if mode == nil {
+		return false
+	}
+
+	if sdlGetClosestDisplayMode(0, mode, unsafe.Pointer(&w.mode)) {
+		w.mode = sdlDisplayMode{}
+		return true
+	}
+
+	if w.flags&sdl_WINDOW_FULLSCREEN_DESKTOP == sdl_WINDOW_FULLSCREEN {
+		sdlSetWindowSize(window, w.fullscreenSize(w.flags))
+	}
+	return false
+}
+
+// The headless backend has a single display with these modes, the first is
+// the desktop mode.
+var headlessDisplayModes = []sdlDisplayMode{
+	{Format: pixelFormatRGB888, W: 1920, H: 1080, RefreshRate: 60},
+	{Format: pixelFormatRGB888, W: 1280, H: 720, RefreshRate: 60},
+	{Format: pixelFormatRGB888, W: 640, H: 480, RefreshRate: 60},
+}
+
+func sdlGetNumVideoDisplays() int {
+	return 1
+}
+
+func sdlGetDisplayName(index int) string {
+	if index != 0 {
+		return ""
+	}
+	return "Headless display"
+}
+
+func sdlGetDisplayBounds(index int, rect unsafe.Pointer) bool {
+	if index != 0 {
+		return headlessSetError("invalid display index")
+	}
+
+	m := headlessDisplayModes[0]
+	*(*sdlRect)(rect) = sdlRect{0, 0, m.W, m.H}
+	return false
+}
+
+func sdlGetDisplayUsableBounds(index int, rect unsafe.Pointer) bool {
+	return sdlGetDisplayBounds(index, rect)
+}
+
+func sdlGetDisplayDPI(index int, ddpi, hdpi, vdpi unsafe.Pointer) bool {
+	if index != 0 {
+		return headlessSetError("invalid display index")
+	}
+
+	for _, p := range []unsafe.Pointer{ddpi, hdpi, vdpi} {
+		*(*float32)(p) = 96
+	}
+	return false
+}
+
+func sdlGetNumDisplayModes(index int) int {
+	if index != 0 {
+		headlessSetError("invalid display index")
+		return -1
+	}
+	return len(headlessDisplayModes)
+}
+
+func sdlGetDisplayMode(display, index int, mode unsafe.Pointer) bool {
+	if display != 0 || index < 0 || index >= len(headlessDisplayModes) {
+		return headlessSetError("invalid display mode index")
+	}
+
+	*(*sdlDisplayMode)(mode) = headlessDisplayModes[index]
+	return false
+}
+
+func sdlGetDesktopDisplayMode(index int, mode unsafe.Pointer) bool {
+	return sdlGetDisplayMode(index, 0, mode)
+}
+
+// sdlGetClosestDisplayMode follows SDL_GetClosestDisplayMode. Of the modes at
+// least as large as the requested size the smallest is selected, a zero format
+// or refresh rate is taken from the desktop mode.
+func sdlGetClosestDisplayMode(index int, mode, closest unsafe.Pointer) bool {
+	if index != 0 {
+		return headlessSetError("invalid display index")
+	}
+
+	m := *(*sdlDisplayMode)(mode)
+	desktop := headlessDisplayModes[0]
+	if m.Format == 0 {
+		m.Format = desktop.Format
+	}
+	if m.RefreshRate == 0 {
+		m.RefreshRate = desktop.RefreshRate
+	}
+
+	var match *sdlDisplayMode
+	for i := range headlessDisplayModes {
+		dm := &headlessDisplayModes[i]
+		if dm.W < m.W || dm.H < m.H {
+			continue
+		}
+		switch {
+		case match == nil || dm.W < match.W || dm.H < match.H:
+			match = dm
+		case dm.Format != match.Format:
+			if dm.Format == m.Format {
+				match = dm
+			}
+		case dm.RefreshRate != match.RefreshRate:
+			if dm.RefreshRate >= m.RefreshRate {
+				match = dm
+			}
+		}
+	}
+
+	if match == nil {
+		return headlessSetError("couldn't find display mode match")
+	}
+
+	*(*sdlDisplayMode)(closest) = *match
+	return false
+}
+
+func sdlSetWindowFullscreen(window uintptr, flags uint32) bool {
+	w, ok := headlessObject(window).(*headlessWindow)
+	if !ok {
+		return headlessSetError("invalid window")
+	}
+
+	// Like SDL the windowed size is restored, fullscreen windows are moved to
+	// the origin of the display.
+	size := w.windowed
+	if flags != 0 {
+		if w.flags&sdl_WINDOW_FULLSCREEN == 0 {
+			w.windowed = w.size
+		}
+		size = w.fullscreenSize(flags)
+		w.position = image.Point{}
+	}
+
+	w.flags = w.flags&^sdl_WINDOW_FULLSCREEN_DESKTOP | flags
+	sdlSetWindowSize(window, size)
+	return false
+}
+
+// fullscreenSize returns the window size in the fullscreen mode given by
+// flags, desktop fullscreen uses the desktop resolution.
+func (w *headlessWindow) fullscreenSize(flags uint32) image.Point {
+	m := headlessDisplayModes[0]
+	if flags&sdl_WINDOW_FULLSCREEN_DESKTOP == sdl_WINDOW_FULLSCREEN {
+		if w.mode.W == 0 {
+			return w.size
+		}
+		m = w.mode
+	}
+	return image.Pt(int(m.W), int(m.H))
+}
+
+func sdlCreateTexture(renderer uintptr, format uint32, backBufferSize image.Point) uintptr {
+	r := image.Rectangle{Max: backBufferSize}
+	t := &headlessTexture{format: format}
+
+	switch format {
+	case pixelFormatABGR8888:
+		t.img = image.NewRGBA(r)
+	case pixelFormatARGB8888, pixelFormatRGB888:
+		t.img = NewBGRA(r)
+	case pixelFormatRGB565:
+		t.img = NewRGB565(r)
+	case pixelFormatYV12, pixelFormatIYUV:
+		t.img = image.NewYCbCr(r, image.YCbCrSubsampleRatio420)
+	case pixelFormatNV12:
+		t.img = NewNV12(r)
+	default:
+		headlessSetError("unsupported texture format")
+		return 0
+	}
+	return headlessHandle(t)
+}
+
+func sdlDestroyTexture(texture uintptr) {
+	headlessRelease(texture)
+}
+
+func headlessTextureRect(t *headlessTexture, rect unsafe.Pointer) image.Rectangle {
+	if rect == nil {
+		return t.img.Bounds()
+	}
+	r := *(*sdlRect)(rect)
+	return image.Rect(int(r.X), int(r.Y), int(r.X+r.W), int(r.Y+r.H)).Intersect(t.img.Bounds())
+}
+
+func sdlUpdateTexture(texture uintptr, rect, data unsafe.Pointer, stride uintptr) bool {
+	t, ok := headlessObject(texture).(*headlessTexture)
+	if !ok {
+		return headlessSetError("invalid texture")
+	}
+	if t.locked {
+		return headlessSetError("texture is locked")
+	}
+
+	r := headlessTextureRect(t, rect)
+	pitch := int(stride)
+	src := headlessMemory(data, pitch*r.Dy())
+
+	switch img := t.img.(type) {
+	case *image.RGBA:
+		copyRows(img.Pix[img.PixOffset(r.Min.X, r.Min.Y):], img.Stride, src, pitch, r.Dx()*4, r.Dy())
+	case *BGRA:
+		copyRows(img.Pix[img.PixOffset(r.Min.X, r.Min.Y):], img.Stride, src, pitch, r.Dx()*4, r.Dy())
+	case *RGB565:
+		copyRows(img.Pix[img.PixOffset(r.Min.X, r.Min.Y):], img.Stride, src, pitch, r.Dx()*2, r.Dy())
+	case *NV12:
+		h := img.Rect.Dy()
+		src = headlessMemory(data, pitch*(h+(h+1)/2))
+		copyRows(img.Pix, img.Stride, src, pitch, img.Rect.Dx(), h)
+		copyRows(img.Pix[img.COffset(0, 0):], img.Stride, src[pitch*h:], pitch, img.Stride, (h+1)/2)
+	default:
+		return headlessSetError("unsupported texture format")
+	}
+	return false
+}
+
+func sdlUpdateYUVTexture(texture uintptr, rect, y unsafe.Pointer, yStride uintptr, u unsafe.Pointer, uStride uintptr, v unsafe.Pointer, vStride uintptr) bool {
+	t, ok := headlessObject(texture).(*headlessTexture)
+	if !ok {
+		return headlessSetError("invalid texture")
+	}
+
+	img, ok := t.img.(*image.YCbCr)
+	if !ok {
+		return headlessSetError("texture is not a planar YUV texture")
+	}
+
+	r := headlessTextureRect(t, rect)
+	cw, ch := (r.Dx()+1)/2, (r.Dy()+1)/2
+
+	copyRows(img.Y[img.YOffset(r.Min.X, r.Min.Y):], img.YStride, headlessMemory(y, int(yStride)*r.Dy()), int(yStride), r.Dx(), r.Dy())
+	copyRows(img.Cb[img.COffset(r.Min.X, r.Min.Y):], img.CStride, headlessMemory(u, int(uStride)*ch), int(uStride), cw, ch)
+	copyRows(img.Cr[img.COffset(r.Min.X, r.Min.Y):], img.CStride, headlessMemory(v, int(vStride)*ch), int(vStride), cw, ch)
+	return false
+}
+
+func sdlLockTexture(texture uintptr, pixels, pitch unsafe.Pointer) bool {
+	t, ok := headlessObject(texture).(*headlessTexture)
+	if !ok {
+		return headlessSetError("invalid texture")
+	}
+
+	img, ok := t.img.(*image.RGBA)
+	if !ok {
+		return headlessSetError("texture format does not support locking")
+	}
+
+	t.locked = true
+	*(*unsafe.Pointer)(pixels) = unsafe.Pointer(&img.Pix[0])
+	*(*int32)(pitch) = int32(img.Stride)
+	return false
+}
+
+func sdlUnlockTexture(texture uintptr) {
+	if t, ok := headlessObject(texture).(*headlessTexture); ok {
+		t.locked = false
+	}
+}
+
+func sdlRenderCopy(renderer, texture uintptr) bool {
+	r, ok := headlessObject(renderer).(*headlessRenderer)
+	if !ok {
+		return headlessSetError("invalid renderer")
+	}
+	r.texture = texture
+	return false
+}
+
+func sdlRenderPresent(renderer uintptr) {
+	r, ok := headlessObject(renderer).(*headlessRenderer)
+	if !ok {
+		return
+	}
+
+	t, ok := headlessObject(r.texture).(*headlessTexture)
+	if !ok {
+		return
+	}
+
+	frame := image.NewRGBA(image.Rectangle{Max: t.img.Bounds().Size()})
+	draw.Draw(frame, frame.Rect, t.img, t.img.Bounds().Min, draw.Src)
+
+	if t.format == pixelFormatRGB888 {
+		for i := 3; i < len(frame.Pix); i += 4 {
+			frame.Pix[i] = 0xff
+		}
+	}
+
+	headlessLock.Lock()
+	headlessCaptured = append(headlessCaptured, frame)
+	if len(headlessCaptured) > maxCapturedFrames {
+		headlessCaptured = headlessCaptured[len(headlessCaptured)-maxCapturedFrames:]
+	}
+	headlessLock.Unlock()
+}
+
+func sdlRenderReadPixels(renderer uintptr, rect unsafe.Pointer, format uint32, pixels unsafe.Pointer, pitch uintptr) bool {
+	r, ok := headlessObject(renderer).(*headlessRenderer)
+	if !ok {
+		return headlessSetError("invalid renderer")
+	}
+
+	w, ok := headlessObject(r.window).(*headlessWindow)
+	if !ok {
+		return headlessSetError("invalid window")
+	}
+
+	t, ok := headlessObject(r.texture).(*headlessTexture)
+	if !ok {
+		return headlessSetError("invalid texture")
+	}
+
+	if format != pixelFormatABGR8888 {
+		return headlessSetError("unsupported pixel format")
+	}
+
+	src := image.NewRGBA(image.Rectangle{Max: t.img.Bounds().Size()})
+	draw.Draw(src, src.Rect, t.img, t.img.Bounds().Min, draw.Src)
+
+	// Emulate the letterboxing applied by SDL_RenderSetLogicalSize.
+	viewport := image.Rectangle{Max: w.size}
+	if r.logicalSize.X != 0 {
+		scale := float64(w.size.X) / float64(r.logicalSize.X)
+		if s := float64(w.size.Y) / float64(r.logicalSize.Y); s < scale {
+			scale = s
+		}
+
+		size := image.Pt(int(float64(r.logicalSize.X)*scale), int(float64(r.logicalSize.Y)*scale))
+		viewport = image.Rectangle{Max: size}.Add(w.size.Sub(size).Div(2))
+	}
+
+	dst := headlessTextureRect(&headlessTexture{img: image.NewRGBA(image.Rectangle{Max: w.size})}, rect)
+	out := &image.RGBA{
+		Pix:    headlessMemory(pixels, int(pitch)*dst.Dy()),
+		Stride: int(pitch),
+		Rect:   dst,
+	}
+
+	area := dst.Intersect(viewport)
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			sx := (x - viewport.Min.X) * src.Rect.Dx() / viewport.Dx()
+			sy := (y - viewport.Min.Y) * src.Rect.Dy() / viewport.Dy()
+			out.SetRGBA(x, y, src.RGBAAt(sx, sy))
+		}
+	}
+	return false
+}
+
+func sdlGetRendererOutputSize(renderer uintptr, w, h unsafe.Pointer) bool {
+	r, ok := headlessObject(renderer).(*headlessRenderer)
+	if !ok {
+		return headlessSetError("invalid renderer")
+	}
+
+	win, ok := headlessObject(r.window).(*headlessWindow)
+	if !ok {
+		return headlessSetError("invalid window")
+	}
+
+	*(*int32)(w) = int32(win.size.X)
+	*(*int32)(h) = int32(win.size.Y)
+	return false
+}
+
+func sdlPollEvent(p unsafe.Pointer) bool {
+	headlessLock.Lock()
+	defer headlessLock.Unlock()
+
+	if len(headlessEvents) == 0 {
+		return false
+	}
+
+	ev := (*sdlEvent)(p)
+	*ev = headlessEvents[0]
+	headlessEvents = headlessEvents[1:]
+	headlessUpdateInputState(ev)
+	return true
+}
+
+// headlessUpdateInputState tracks the keyboard and mouse state, and the window
+// size, from polled events, like SDL does.
+func headlessUpdateInputState(ev *sdlEvent) {
+	up := unsafe.Pointer(ev)
+
+	switch *(*anyEvent)(up) {
+	case sdlWindowEventType:
+		t := (*WindowEvent)(up)
+		if w, ok := headlessHandles[uintptr(t.WindowID)].(*headlessWindow); ok && t.Event == WindowSizeChanged {
+			w.size = t.Size()
+		}
+	case sdlKeyDownEventType:
+		ks := (*KeyDownEvent)(up).Keysym
+		if ks.Scancode >= 0 && ks.Scancode < numScancodes {
+			headlessKeyboard[ks.Scancode] = 1
+		}
+		headlessModState = ks.Mod
+	case sdlKeyUpEventType:
+		ks := (*KeyUpEvent)(up).Keysym
+		if ks.Scancode >= 0 && ks.Scancode < numScancodes {
+			headlessKeyboard[ks.Scancode] = 0
+		}
+		headlessModState = ks.Mod
+	case sdlMouseMotionEventType:
+		t := (*MouseMotionEvent)(up)
+		headlessMouse = image.Pt(int(t.X), int(t.Y))
+	case sdlMouseButtonDownEventType:
+		t := (*MouseButtonEvent)(up)
+		headlessMouse = image.Pt(int(t.X), int(t.Y))
+		headlessButtons |= ButtonMask(t.Button)
+	case sdlMouseButtonUpEventType:
+		t := (*MouseButtonEvent)(up)
+		headlessMouse = image.Pt(int(t.X), int(t.Y))
+		headlessButtons &^= ButtonMask(t.Button)
+	}
+}
+
+func sdlStartTextInput() {
+}
+
+func sdlStopTextInput() {
+}
+
+func sdlSetTextInputRect(rect unsafe.Pointer) {
+}
+
+func sdlGetKeyFromScancode(s Scancode) Keycode {
+	if s < 0 || s >= numScancodes {
+		return UnknownKey
+	}
+	return defaultKeymap[s]
+}
+
+func sdlGetKeyboardState() []uint8 {
+	headlessLock.Lock()
+	defer headlessLock.Unlock()
+
+	state := headlessKeyboard
+	return state[:]
+}
+
+func sdlGetModState() uint16 {
+	headlessLock.Lock()
+	defer headlessLock.Unlock()
+	return headlessModState
+}
+
+func sdlGetMouseState(x, y unsafe.Pointer) uint32 {
+	headlessLock.Lock()
+	defer headlessLock.Unlock()
+
+	*(*int32)(x) = int32(headlessMouse.X)
+	*(*int32)(y) = int32(headlessMouse.Y)
+	return headlessButtons
+}
+
+// The headless backend has no joysticks, controller events can still be
+// injected.
+
+func sdlNumJoysticks() int {
+	return 0
+}
+
+func sdlIsGameController(index int) bool {
+	return false
+}
+
+func sdlGameControllerOpen(index int) uintptr {
+	headlessSetError("There are 0 joysticks available")
+	return 0
+}
+
+func sdlGameControllerClose(controller uintptr) {
+}
+
+func sdlGameControllerName(controller uintptr) string {
+	return ""
+}
+
+func sdlGameControllerGetJoystick(controller uintptr) uintptr {
+	return 0
+}
+
+func sdlGameControllerGetButton(controller uintptr, button ControllerButton) bool {
+	return false
+}
+
+func sdlGameControllerGetAxis(controller uintptr, axis ControllerAxis) int16 {
+	return 0
+}
+
+func sdlGameControllerAddMapping(mapping string) int {
+	if strings.Count(mapping, ",") < 2 {
+		headlessSetError("Couldn't parse controller mapping")
+		return -1
+	}
+	return 1
+}
+
+func sdlJoystickInstanceID(joystick uintptr) int32 {
+	return -1
+}
+
+func sdlJoystickGetGUID(joystick uintptr) [16]byte {
+	return [16]byte{}
+}
+
+func sdlGameControllerRumble(controller uintptr, low, high uint16, ms uint32) bool {
+	return headlessSetError("That operation is not supported")
+}
+
+func sdlGameControllerRumbleTriggers(controller uintptr, left, right uint16, ms uint32) bool {
+	return headlessSetError("That operation is not supported")
+}
+
+func sdlGameControllerHasRumble(controller uintptr) int {
+	return 0
+}
+
+func sdlGameControllerHasRumbleTriggers(controller uintptr) int {
+	return 0
+}
+
+const headlessAudioDeviceName = "Headless audio device"
+
+func sdlAudioCallback() uintptr {
+	return 1
+}
+
+func sdlGetNumAudioDevices(capture bool) int {
+	return 1
+}
+
+func sdlGetAudioDeviceName(index int, capture bool) string {
+	if index != 0 {
+		return ""
+	}
+	return headlessAudioDeviceName
+}
+
+// sdlOpenAudioDevice opens a device that consumes a buffer of samples every
+// buffer period, played samples are kept for CapturedAudio. Capture devices
+// record silence.
+func sdlOpenAudioDevice(name string, capture bool, desired, obtained unsafe.Pointer) uint32 {
+	if name != "" && name != headlessAudioDeviceName {
+		headlessSetError("No such device")
+		return 0
+	}
+
+	spec := *(*sdlAudioSpec)(desired)
+	if spec.Freq <= 0 || spec.Channels == 0 || spec.Samples == 0 {
+		headlessSetError("Invalid audio spec")
+		return 0
+	}
+
+	spec.Silence = 0
+	spec.Size = uint32(spec.Samples) * uint32(spec.Channels) * uint32(AudioFormat(spec.Format).Size())
+	*(*sdlAudioSpec)(obtained) = spec
+
+	d := &headlessAudioDevice{spec: spec, paused: true, done: make(chan struct{})}
+	period := time.Duration(spec.Samples) * time.Second / time.Duration(spec.Freq)
+
+	d.wg.Add(1)
+	go d.run(period, capture)
+	return uint32(headlessHandle(d))
+}
+
+func (d *headlessAudioDevice) run(period time.Duration, capture bool) {
+	defer d.wg.Done()
+
+	t := time.NewTicker(period)
+	defer t.Stop()
+
+	buf := make([]byte, d.spec.Size)
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-t.C:
+		}
+
+		headlessLock.Lock()
+		paused := d.paused
+		headlessLock.Unlock()
+
+		if paused || (capture && d.spec.Callback == 0) {
+			continue
+		}
+
+		switch {
+		case d.spec.Callback == 0:
+			headlessLock.Lock()
+			n := copy(buf, d.queue)
+			d.queue = d.queue[n:]
+			headlessLock.Unlock()
+
+			for i := n; i < len(buf); i++ {
+				buf[i] = d.spec.Silence
+			}
+		case capture:
+			for i := range buf {
+				buf[i] = d.spec.Silence
+			}
+			audioCallback(d.spec.Userdata, buf)
+			continue
+		default:
+			audioCallback(d.spec.Userdata, buf)
+		}
+
+		headlessLock.Lock()
+		headlessAudio = append(headlessAudio, buf...)
+		if n := len(headlessAudio); n > maxCapturedAudio {
+			headlessAudio = append([]byte(nil), headlessAudio[n-maxCapturedAudio:]...)
+		}
+		headlessLock.Unlock()
+	}
+}
+
+func (d *headlessAudioDevice) close() {
+	close(d.done)
+	d.wg.Wait()
+}
+
+func sdlPauseAudioDevice(dev uint32, pause bool) {
+	if d, ok := headlessObject(uintptr(dev)).(*headlessAudioDevice); ok {
+		headlessLock.Lock()
+		d.paused = pause
+		headlessLock.Unlock()
+	}
+}
+
+func sdlCloseAudioDevice(dev uint32) {
+	if d, ok := headlessObject(uintptr(dev)).(*headlessAudioDevice); ok {
+		headlessRelease(uintptr(dev))
+		d.close()
+	}
+}
+
+func sdlQueueAudio(dev uint32, data unsafe.Pointer, n int) bool {
+	d, ok := headlessObject(uintptr(dev)).(*headlessAudioDevice)
+	if !ok || d.spec.Callback != 0 {
+		return headlessSetError("Audio device has a callback, queueing not allowed")
+	}
+
+	headlessLock.Lock()
+	d.queue = append(d.queue, headlessMemory(data, n)...)
+	headlessLock.Unlock()
+	return false
+}
+
+func sdlGetQueuedAudioSize(dev uint32) int {
+	if d, ok := headlessObject(uintptr(dev)).(*headlessAudioDevice); ok {
+		headlessLock.Lock()
+		defer headlessLock.Unlock()
+		return len(d.queue)
+	}
+	return 0
+}
+
+func sdlClearQueuedAudio(dev uint32) {
+	if d, ok := headlessObject(uintptr(dev)).(*headlessAudioDevice); ok {
+		headlessLock.Lock()
+		d.queue = nil
+		headlessLock.Unlock()
+	}
+}
@@ -0,0 +1,771 @@
+// +build headless
+
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testSize = image.Pt(16, 16)
+
+// runHeadless runs f with a testSize window. Initialize calls f on another
+// goroutine, so f reports failures by returning an error instead of calling
+// t.Fatal.
+func runHeadless(t *testing.T, f func() error, configs ...Config) {
+	t.Helper()
+
+	CapturedFrames()
+	configs = append([]Config{ConfigWithRenderer(testSize, image.Point{})}, configs...)
+	if err := Initialize(f, configs...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func fill(img draw.Image, c color.Color) image.Image {
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+func solidImage(size image.Point, c color.Color) image.Image {
+	return fill(image.NewRGBA(image.Rectangle{Max: size}), c)
+}
+
+func lastFrame() (*image.RGBA, error) {
+	frames := CapturedFrames()
+	if len(frames) == 0 {
+		return nil, errors.New("no frame was presented")
+	}
+	return frames[len(frames)-1], nil
+}
+
+// checkColor verifies that r of img has the color want, within tolerance.
+func checkColor(img image.Image, r image.Rectangle, want color.RGBA, tolerance int) error {
+	diff := func(a, b uint8) int {
+		if a > b {
+			return int(a - b)
+		}
+		return int(b - a)
+	}
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			if diff(c.R, want.R) > tolerance || diff(c.G, want.G) > tolerance || diff(c.B, want.B) > tolerance || diff(c.A, want.A) > tolerance {
+				return fmt.Errorf("pixel at (%d,%d) is %v, expected %v", x, y, c, want)
+			}
+		}
+	}
+	return nil
+}
+
+func checkLastFrame(r image.Rectangle, want color.RGBA, tolerance int) error {
+	frame, err := lastFrame()
+	if err != nil {
+		return err
+	}
+	return checkColor(frame, r, want, tolerance)
+}
+
+func TestPresentPixelFormats(t *testing.T) {
+	want := color.RGBA{200, 100, 50, 255}
+
+	ycbcr := func(r image.Rectangle) image.Image {
+		img := image.NewYCbCr(r, image.YCbCrSubsampleRatio420)
+		y, cb, cr := color.RGBToYCbCr(want.R, want.G, want.B)
+		for i := range img.Y {
+			img.Y[i] = y
+		}
+		for i := range img.Cb {
+			img.Cb[i], img.Cr[i] = cb, cr
+		}
+		return img
+	}
+
+	tests := []struct {
+		name      string
+		format    PixelFormat
+		image     func(r image.Rectangle) image.Image
+		tolerance int
+	}{
+		{"ABGR8888", PixelFormatABGR8888, func(r image.Rectangle) image.Image { return fill(image.NewRGBA(r), want) }, 0},
+		{"ABGR8888 converted", PixelFormatABGR8888, func(r image.Rectangle) image.Image { return fill(NewBGRA(r), want) }, 0},
+		{"ARGB8888", PixelFormatARGB8888, func(r image.Rectangle) image.Image { return fill(NewBGRA(r), want) }, 0},
+		{"ARGB8888 converted", PixelFormatARGB8888, func(r image.Rectangle) image.Image { return fill(image.NewRGBA(r), want) }, 0},
+		{"RGB888", PixelFormatRGB888, func(r image.Rectangle) image.Image { return fill(NewBGRA(r), want) }, 0},
+		{"RGB565", PixelFormatRGB565, func(r image.Rectangle) image.Image { return fill(NewRGB565(r), want) }, 8},
+		{"YV12", PixelFormatYV12, ycbcr, 4},
+		{"IYUV", PixelFormatIYUV, ycbcr, 4},
+		{"NV12", PixelFormatNV12, func(r image.Rectangle) image.Image { return fill(NewNV12(r), want) }, 4},
+		{"NV12 converted", PixelFormatNV12, func(r image.Rectangle) image.Image { return fill(image.NewRGBA(r), want) }, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runHeadless(t, func() error {
+				if _, err := Present(tt.image(image.Rectangle{Max: testSize})); err != nil {
+					return err
+				}
+				return checkLastFrame(image.Rectangle{Max: testSize}, want, tt.tolerance)
+			}, ConfigWithPixelFormat(tt.format))
+		})
+	}
+}
+
+func TestPresentRegions(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	region := image.Rect(4, 4, 8, 12)
+
+	tests := []struct {
+		name    string
+		configs []Config
+	}{
+		{"direct", nil},
+		{"pipelined", []Config{ConfigWithPipeline(2)}},
+		{"NV12", []Config{ConfigWithPixelFormat(PixelFormatNV12)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runHeadless(t, func() error {
+				wg, err := Present(solidImage(testSize, red))
+				if err != nil {
+					return err
+				}
+				wg.Wait()
+
+				wg, err = PresentRegions(solidImage(testSize, blue), []image.Rectangle{region})
+				if err != nil {
+					return err
+				}
+				wg.Wait()
+
+				frame, err := lastFrame()
+				if err != nil {
+					return err
+				}
+				if err := checkColor(frame, region, blue, 4); err != nil {
+					return err
+				}
+				if err := checkColor(frame, image.Rect(0, 0, testSize.X, region.Min.Y), red, 4); err != nil {
+					return err
+				}
+				return checkColor(frame, image.Rect(region.Max.X, 0, testSize.X, testSize.Y), red, 4)
+			}, tt.configs...)
+		})
+	}
+}
+
+func TestPipelineFrames(t *testing.T) {
+	colors := []color.RGBA{{255, 0, 0, 255}, {0, 255, 0, 255}, {0, 0, 255, 255}, {255, 255, 255, 255}}
+
+	runHeadless(t, func() error {
+		var hooked int
+		SetFrameHook(func(image.Image) { hooked++ })
+
+		for i, c := range colors {
+			wg, err := Present(solidImage(testSize, c))
+			if err != nil {
+				return err
+			}
+			if i == len(colors)-1 {
+				wg.Wait()
+			}
+		}
+
+		frames := CapturedFrames()
+		if len(frames) != len(colors) {
+			return fmt.Errorf("%d frames were presented, expected %d", len(frames), len(colors))
+		}
+		for i, frame := range frames {
+			if err := checkColor(frame, frame.Rect, colors[i], 0); err != nil {
+				return err
+			}
+		}
+
+		if _, err := Screenshot(); err != nil {
+			return err
+		}
+		if hooked != len(colors) {
+			return fmt.Errorf("frame hook was called %d times, expected %d", hooked, len(colors))
+		}
+		return nil
+	}, ConfigWithPipeline(2))
+}
+
+// blockMainThread keeps the main thread busy until the returned function is
+// called, which waits for the blocking command to return.
+func blockMainThread() func() {
+	entered, release, done := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	go func() {
+		sendCommand(false, func() error {
+			close(entered)
+			<-release
+			return nil
+		})
+		close(done)
+	}()
+
+	<-entered
+	return func() {
+		close(release)
+		<-done
+	}
+}
+
+// returnsWithin reports an error if f does not return within a second.
+func returnsWithin(f func() error) error {
+	result := make(chan error, 1)
+	go func() { result <- f() }()
+
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(time.Second):
+		return errors.New("call blocked")
+	}
+}
+
+func TestPipelineDoesNotWaitForMainThread(t *testing.T) {
+	runHeadless(t, func() error {
+		img := solidImage(testSize, color.RGBA{255, 0, 0, 255})
+		release := blockMainThread()
+
+		var wg *sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			err := returnsWithin(func() (err error) {
+				wg, err = Present(img)
+				return err
+			})
+			if err != nil {
+				release()
+				return err
+			}
+		}
+
+		_, err := TryPresent(img)
+		release()
+		if err != ErrPipelineFull {
+			return fmt.Errorf("TryPresent returned %v, expected %v", err, ErrPipelineFull)
+		}
+
+		wg.Wait()
+		if n := len(CapturedFrames()); n != 2 {
+			return fmt.Errorf("%d frames were presented, expected 2", n)
+		}
+		return nil
+	}, ConfigWithPipeline(2))
+}
+
+func TestSetPalette(t *testing.T) {
+	black := color.RGBA{0, 0, 0, 255}
+	white := color.RGBA{255, 255, 255, 255}
+
+	runHeadless(t, func() error {
+		img := image.NewPaletted(image.Rectangle{Max: testSize}, nil)
+		if _, err := Present(img); err != nil {
+			return err
+		}
+		if err := checkLastFrame(img.Rect, black, 0); err != nil {
+			return err
+		}
+
+		frames := FrameStats().Frames
+		if err := SetPalette(color.Palette{white}); err != nil {
+			return err
+		}
+		if err := checkLastFrame(img.Rect, white, 0); err != nil {
+			return err
+		}
+		if n := FrameStats().Frames; n != frames {
+			return fmt.Errorf("SetPalette changed the frame count from %d to %d", frames, n)
+		}
+
+		if _, err := Present(solidImage(testSize, white)); err == nil {
+			return errors.New("expected an error when presenting an image that is not paletted")
+		}
+		return nil
+	}, ConfigWithPalette(color.Palette{black}))
+}
+
+func TestLockFrame(t *testing.T) {
+	green := color.RGBA{0, 255, 0, 255}
+
+	runHeadless(t, func() error {
+		frame, err := LockFrame()
+		if err != nil {
+			return err
+		}
+		fill(frame, green)
+
+		if _, err := Present(solidImage(testSize, green)); err != errFrameLocked {
+			return fmt.Errorf("Present of a locked frame returned %v, expected %v", err, errFrameLocked)
+		}
+		if _, err := Screenshot(); err != errFrameLocked {
+			return fmt.Errorf("Screenshot of a locked frame returned %v, expected %v", err, errFrameLocked)
+		}
+
+		if err := UnlockAndPresent(); err != nil {
+			return err
+		}
+		return checkLastFrame(image.Rectangle{Max: testSize}, green, 0)
+	})
+}
+
+func TestScreenshot(t *testing.T) {
+	tests := []struct {
+		name    string
+		logical image.Point
+	}{
+		{"window size", image.Point{}},
+		{"logical size", image.Pt(8, 8)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := color.RGBA{10, 20, 30, 255}
+
+			runHeadless(t, func() error {
+				if _, err := Present(solidImage(BackBufferSize(), want)); err != nil {
+					return err
+				}
+
+				img, err := Screenshot()
+				if err != nil {
+					return err
+				}
+				if img.Rect.Size() != testSize {
+					return fmt.Errorf("screenshot is %v, expected %v", img.Rect.Size(), testSize)
+				}
+				return checkColor(img, img.Rect, want, 0)
+			}, ConfigWithRenderer(testSize, tt.logical))
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    RecordFormat
+		opts      []RecorderOption
+		recording bool
+		err       error
+	}{
+		{"GIF", RecordGIF, nil, true, nil},
+		{"APNG", RecordAPNG, nil, true, nil},
+		{"Y4M", RecordY4M, nil, true, nil},
+		{"frame skip", RecordGIF, []RecorderOption{RecordWithFrameSkip(1)}, true, nil},
+		{"memory limit", RecordGIF, []RecorderOption{RecordWithMaxMemory(testSize.X * testSize.Y * 2)}, false, ErrRecordingTruncated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			rec, err := NewRecorder(&buf, tt.format, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			runHeadless(t, func() error {
+				if err := rec.Start(DefaultWindow()); err != nil {
+					return err
+				}
+				for i := 0; i < 4; i++ {
+					if _, err := Present(solidImage(testSize, color.RGBA{uint8(i * 50), 0, 0, 255})); err != nil {
+						return err
+					}
+				}
+
+				if rec.Recording() != tt.recording {
+					return fmt.Errorf("Recording returned %v, expected %v", rec.Recording(), tt.recording)
+				}
+				if err := rec.Stop(); err != tt.err {
+					return fmt.Errorf("Stop returned %v, expected %v", err, tt.err)
+				}
+				if rec.Recording() {
+					return errors.New("Recording returned true after Stop")
+				}
+				return nil
+			})
+
+			if buf.Len() == 0 {
+				t.Error("nothing was written")
+			}
+		})
+	}
+}
+
+func TestRecorderRestoresFrameHook(t *testing.T) {
+	runHeadless(t, func() error {
+		var hooked int
+		SetFrameHook(func(image.Image) { hooked++ })
+
+		rec, err := NewRecorder(new(bytes.Buffer), RecordY4M)
+		if err != nil {
+			return err
+		}
+		if err := rec.Start(DefaultWindow()); err != nil {
+			return err
+		}
+		if err := rec.Start(DefaultWindow()); err == nil {
+			return errors.New("expected an error when starting the recorder twice")
+		}
+
+		img := solidImage(testSize, color.RGBA{255, 0, 0, 255})
+		if _, err := Present(img); err != nil {
+			return err
+		}
+		if err := rec.Stop(); err != nil {
+			return err
+		}
+		if _, err := Present(img); err != nil {
+			return err
+		}
+
+		if hooked != 2 {
+			return fmt.Errorf("frame hook was called %d times, expected 2", hooked)
+		}
+		return nil
+	})
+}
+
+func TestResizeUploadsFullFrame(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	size := image.Pt(24, 20)
+
+	runHeadless(t, func() error {
+		if _, err := Present(solidImage(testSize, red)); err != nil {
+			return err
+		}
+
+		if err := DefaultWindow().SetSize(size); err != nil {
+			return err
+		}
+		for range Events() {
+		}
+
+		if s := BackBufferSize(); s != size {
+			return fmt.Errorf("back-buffer is %v, expected %v", s, size)
+		}
+		if _, err := Present(solidImage(testSize, red)); err == nil {
+			return errors.New("expected an error when presenting a frame of the old size")
+		}
+
+		// The content of the new texture is undefined, so the first frame
+		// after the resize is uploaded in full even if one region changed.
+		if _, err := PresentRegions(solidImage(size, blue), []image.Rectangle{image.Rect(0, 0, 2, 2)}); err != nil {
+			return err
+		}
+		return checkLastFrame(image.Rectangle{Max: size}, blue, 0)
+	}, ConfigWithResizable(true))
+}
+
+type unsupportedEvent struct{}
+
+func (unsupportedEvent) Release() {}
+
+func TestInjectEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		ev   Event
+		want func(Event) bool
+	}{
+		{"quit", &QuitEvent{}, func(ev Event) bool {
+			_, ok := ev.(*QuitEvent)
+			return ok
+		}},
+		{"key down", &KeyDownEvent{}, func(ev Event) bool {
+			_, ok := ev.(*KeyDownEvent)
+			return ok
+		}},
+		{"mouse button up", &MouseButtonEvent{Button: 1}, func(ev Event) bool {
+			e, ok := ev.(*MouseButtonEvent)
+			return ok && e.Button == 1
+		}},
+		{"audio underrun", &AudioUnderrunEvent{Which: 7}, func(ev Event) bool {
+			e, ok := ev.(*AudioUnderrunEvent)
+			return ok && e.Which == 7
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runHeadless(t, func() error {
+				if err := InjectEvent(tt.ev); err != nil {
+					return err
+				}
+
+				found := false
+				for ev := range Events() {
+					found = found || tt.want(ev)
+					ev.Release()
+				}
+				if !found {
+					return errors.New("injected event was not returned by Events")
+				}
+				return nil
+			})
+		})
+	}
+
+	if err := InjectEvent(unsupportedEvent{}); err == nil {
+		t.Error("expected an error for an unsupported event type")
+	}
+}
+
+func TestQueuedAudioUnderrun(t *testing.T) {
+	runHeadless(t, func() error {
+		spec := AudioSpec{Freq: 8000, Format: AudioS16, Channels: 1, Samples: 256}
+		d, err := OpenQueuedAudioDevice("", spec)
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+
+		// The queue is not played while the device is paused.
+		const frames = 400
+		if err := d.Queue(make([]byte, frames*spec.FrameSize())); err != nil {
+			return err
+		}
+		latency, err := d.Latency()
+		if err != nil {
+			return err
+		}
+		if want := time.Duration(frames+spec.Samples) * time.Second / time.Duration(spec.Freq); latency != want {
+			return fmt.Errorf("latency is %v, expected %v", latency, want)
+		}
+
+		if err := d.Resume(); err != nil {
+			return err
+		}
+		for deadline := time.Now().Add(2 * time.Second); d.Underruns() == 0; {
+			if time.Now().After(deadline) {
+				return errors.New("no underrun was counted")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if n := d.Underruns(); n != 1 {
+			return fmt.Errorf("%d underruns were counted, expected 1", n)
+		}
+
+		found := false
+		for ev := range Events() {
+			if e, ok := ev.(*AudioUnderrunEvent); ok && e.Which == d.ID() {
+				found = true
+			}
+			ev.Release()
+		}
+		if !found {
+			return errors.New("no underrun event was sent")
+		}
+		return nil
+	}, ConfigWithAudio())
+}
+
+func TestWindowsPresentConcurrently(t *testing.T) {
+	runHeadless(t, func() error {
+		w, err := NewWindow(ConfigWithRenderer(testSize, image.Point{}), ConfigWithPipeline(2))
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+
+		// The default window is not pipelined, which must not make the
+		// pipelined window wait for the main thread.
+		img := solidImage(testSize, color.RGBA{255, 0, 0, 255})
+		release := blockMainThread()
+		for i := 0; i < 2; i++ {
+			if err := returnsWithin(func() error {
+				_, err := w.Present(img)
+				return err
+			}); err != nil {
+				release()
+				return err
+			}
+		}
+		release()
+
+		const frames = 10
+		errs := make(chan error, 2)
+		for _, present := range []func(image.Image) (*sync.WaitGroup, error){DefaultWindow().Present, w.Present} {
+			go func(present func(image.Image) (*sync.WaitGroup, error)) {
+				var wg *sync.WaitGroup
+				for i := 0; i < frames; i++ {
+					var err error
+					if wg, err = present(img); err != nil {
+						errs <- err
+						return
+					}
+				}
+				wg.Wait()
+				errs <- nil
+			}(present)
+		}
+
+		for i := 0; i < 2; i++ {
+			if err := <-errs; err != nil {
+				return err
+			}
+		}
+		if n := len(CapturedFrames()); n != 2+2*frames {
+			return fmt.Errorf("%d frames were presented, expected %d", n, 2+2*frames)
+		}
+		return nil
+	})
+}
+
+func TestDisplays(t *testing.T) {
+	runHeadless(t, func() error {
+		displays, err := Displays()
+		if err != nil {
+			return err
+		}
+		if len(displays) != 1 {
+			return fmt.Errorf("%d displays, expected 1", len(displays))
+		}
+
+		d := displays[0]
+		if bounds := image.Rect(0, 0, 1920, 1080); d.Bounds != bounds || d.UsableBounds != bounds {
+			return fmt.Errorf("display bounds are %v and %v, expected %v", d.Bounds, d.UsableBounds, bounds)
+		}
+		if d.DiagonalDPI != 96 || d.HorizontalDPI != 96 || d.VerticalDPI != 96 {
+			return fmt.Errorf("display DPI is %v, %v and %v, expected 96", d.DiagonalDPI, d.HorizontalDPI, d.VerticalDPI)
+		}
+		if d.DesktopMode.Size != image.Pt(1920, 1080) || d.DesktopMode.RefreshRate != 60 {
+			return fmt.Errorf("desktop mode is %+v", d.DesktopMode)
+		}
+
+		if len(d.Modes) != 3 {
+			return fmt.Errorf("%d display modes, expected 3", len(d.Modes))
+		}
+		for i := 1; i < len(d.Modes); i++ {
+			if a, b := d.Modes[i-1].Size, d.Modes[i].Size; a.X*a.Y <= b.X*b.Y {
+				return fmt.Errorf("display mode %v is listed before %v", a, b)
+			}
+		}
+		return nil
+	})
+}
+
+func TestDisplayModeSelection(t *testing.T) {
+	tests := []struct {
+		name string
+		mode DisplayMode
+		want image.Point
+	}{
+		{"exact", DisplayMode{Size: image.Pt(640, 480)}, image.Pt(640, 480)},
+		{"larger", DisplayMode{Size: image.Pt(800, 600)}, image.Pt(1280, 720)},
+		{"refresh rate", DisplayMode{Size: image.Pt(1280, 720), RefreshRate: 60}, image.Pt(1280, 720)},
+		{"window size", DisplayMode{}, image.Pt(640, 480)},
+		{"too large", DisplayMode{Size: image.Pt(2560, 1440)}, image.Point{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runHeadless(t, func() error {
+				w, err := NewWindow(ConfigWithRenderer(testSize, image.Point{}), ConfigWithDisplayMode(tt.mode), ConfigWithFullscreen(FullscreenExclusive))
+				if tt.want == (image.Point{}) {
+					if err == nil {
+						w.Close()
+						return errors.New("the window was created without a matching display mode")
+					}
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				defer w.Close()
+
+				size, err := w.Size()
+				if err != nil {
+					return err
+				}
+				if size != tt.want {
+					return fmt.Errorf("fullscreen size is %v, expected %v", size, tt.want)
+				}
+				return nil
+			})
+		})
+	}
+}
+
+func TestFullscreenRestoresWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs []Config
+	}{
+		{"windowed", nil},
+		{"created fullscreen", []Config{ConfigWithFullscreen(FullscreenDesktop)}},
+		{"created exclusive", []Config{ConfigWithDisplayMode(DisplayMode{Size: image.Pt(640, 480)}), ConfigWithFullscreen(FullscreenExclusive)}},
+	}
+
+	checkBounds := func(w *Window, pos, size image.Point) error {
+		p, err := w.Position()
+		if err != nil {
+			return err
+		}
+		s, err := w.Size()
+		if err != nil {
+			return err
+		}
+		if p != pos || s != size {
+			return fmt.Errorf("window is at %v with size %v, expected %v and %v", p, s, pos, size)
+		}
+		return nil
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runHeadless(t, func() error {
+				w := DefaultWindow()
+
+				// A window created fullscreen returns to the size it was
+				// created with.
+				if len(tt.configs) > 0 {
+					if err := w.SetFullscreen(Windowed); err != nil {
+						return err
+					}
+					if err := checkBounds(w, image.Point{}, testSize); err != nil {
+						return err
+					}
+				}
+
+				pos, size := image.Pt(100, 50), image.Pt(320, 240)
+				if err := w.SetPosition(pos); err != nil {
+					return err
+				}
+				if err := w.SetSize(size); err != nil {
+					return err
+				}
+
+				if err := w.SetFullscreen(FullscreenExclusive); err != nil {
+					return err
+				}
+				if fullscreen, err := w.IsFullscreen(); err != nil || !fullscreen {
+					return fmt.Errorf("the window is not fullscreen: %v", err)
+				}
+				if p, err := w.Position(); err != nil || p == pos {
+					return fmt.Errorf("the fullscreen window was not moved: %v", err)
+				}
+
+				if err := w.SetFullscreen(Windowed); err != nil {
+					return err
+				}
+				return checkBounds(w, pos, size)
+			}, tt.configs...)
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// +build !windows
+// +build !windows,!headless
 
 /* This Source Code Form is subject to the terms of the Mozilla Public
  * License, v. 2.0. If a copy of the MPL was not distributed with this
@@ -8,7 +8,51 @@ package vsdl
 
 /*
 #cgo linux freebsd darwin pkg-config: sdl2
+#include <stdlib.h>
 #include <SDL.h>
+
+extern void vsdlAudioCallback(void *, Uint8 *, int);
+
+static int vsdlSetWindowAlwaysOnTop(SDL_Window *w, SDL_bool b) {
+#if SDL_VERSION_ATLEAST(2, 0, 16)
+	SDL_SetWindowAlwaysOnTop(w, b);
+	return 0;
+#else
+	return SDL_Unsupported();
+#endif
+}
+
+static int vsdlGameControllerRumble(SDL_GameController *c, Uint16 low, Uint16 high, Uint32 ms) {
+#if SDL_VERSION_ATLEAST(2, 0, 9)
+	return SDL_GameControllerRumble(c, low, high, ms);
+#else
+	return SDL_Unsupported();
+#endif
+}
+
+static int vsdlGameControllerRumbleTriggers(SDL_GameController *c, Uint16 left, Uint16 right, Uint32 ms) {
+#if SDL_VERSION_ATLEAST(2, 0, 14)
+	return SDL_GameControllerRumbleTriggers(c, left, right, ms);
+#else
+	return SDL_Unsupported();
+#endif
+}
+
+static int vsdlGameControllerHasRumble(SDL_GameController *c) {
+#if SDL_VERSION_ATLEAST(2, 0, 18)
+	return SDL_GameControllerHasRumble(c);
+#else
+	return -1;
+#endif
+}
+
+static int vsdlGameControllerHasRumbleTriggers(SDL_GameController *c) {
+#if SDL_VERSION_ATLEAST(2, 0, 18)
+	return SDL_GameControllerHasRumbleTriggers(c);
+#else
+	return -1;
+#endif
+}
 */
 import "C"
 import (
@@ -59,19 +103,27 @@ func sdlQuit() {
 	C.SDL_Quit()
 }
 
-func sdlCreateWindowAndRenderer(windowSize image.Point, windowPtr, rendererPtr uintptr) bool {
-	ret := C.SDL_CreateWindowAndRenderer(
-		C.int(windowSize.X),
-		C.int(windowSize.Y),
-		0,
-		(**C.SDL_Window)(unsafe.Pointer(windowPtr)),
-		(**C.SDL_Renderer)(unsafe.Pointer(rendererPtr)),
-	)
-	if ret == 0 {
-		C.SDL_ShowCursor(0)
-		return false
+func sdlCreateWindowAndRenderer(windowSize image.Point, display int, windowFlags, rendererFlags uint32, windowPtr, rendererPtr unsafe.Pointer) bool {
+	title := C.CString("")
+	defer C.free(unsafe.Pointer(title))
+
+	pos := C.int(sdl_WINDOWPOS_UNDEFINED | display)
+	w := C.SDL_CreateWindow(title, pos, pos, C.int(windowSize.X), C.int(windowSize.Y), C.Uint32(windowFlags))
+	if w == nil {
+		return true
 	}
-	return true
+
+	r := C.SDL_CreateRenderer(w, -1, C.Uint32(rendererFlags))
+	if r == nil {
+		C.SDL_DestroyWindow(w)
+		return true
+	}
+
+	*(**C.SDL_Window)(windowPtr) = w
+	*(**C.SDL_Renderer)(rendererPtr) = r
+
+	C.SDL_ShowCursor(0)
+	return false
 }
 
 func sdlDestroyRendererAndWindow(window, renderer uintptr) {
@@ -89,30 +141,163 @@ func sdlRenderSetLogicalSize(renderer uintptr, logicalSize image.Point) bool {
 	return C.SDL_RenderSetLogicalSize((*C.SDL_Renderer)(unsafe.Pointer(renderer)), C.int(logicalSize.X), C.int(logicalSize.Y)) != 0
 }
 
-func sdlToggleFullscreen(window uintptr) (bool, error) {
-	w := (*C.SDL_Window)(unsafe.Pointer(window))
-	flags := (uint32)(C.SDL_GetWindowFlags(w))
-	isFullscreen := (flags & sdl_WINDOW_FULLSCREEN) != 0
+func sdlGetWindowID(window uintptr) uint32 {
+	return uint32(C.SDL_GetWindowID((*C.SDL_Window)(unsafe.Pointer(window))))
+}
+
+func sdlGetWindowFlags(window uintptr) uint32 {
+	return uint32(C.SDL_GetWindowFlags((*C.SDL_Window)(unsafe.Pointer(window))))
+}
+
+func sdlSetWindowTitle(window uintptr, title string) {
+	str := C.CString(title)
+	defer C.free(unsafe.Pointer(str))
+	C.SDL_SetWindowTitle((*C.SDL_Window)(unsafe.Pointer(window)), str)
+}
+
+func sdlGetWindowTitle(window uintptr) string {
+	return C.GoString(C.SDL_GetWindowTitle((*C.SDL_Window)(unsafe.Pointer(window))))
+}
+
+func sdlSetWindowIcon(window uintptr, icon *image.RGBA) bool {
+	// The surface references the pixels until it is freed, so they are copied
+	// to C memory.
+	pixels := C.CBytes(icon.Pix)
+	defer C.free(pixels)
 
-	if isFullscreen {
-		C.SDL_SetWindowFullscreen(w, C.Uint32(0))
-		return false, sdlToGoError()
+	size := icon.Rect.Size()
+	surface := C.SDL_CreateRGBSurfaceWithFormatFrom(pixels, C.int(size.X), C.int(size.Y), 32, C.int(icon.Stride), C.Uint32(pixelFormatABGR8888))
+	if surface == nil {
+		return true
 	}
 
-	C.SDL_SetWindowFullscreen(w, C.Uint32(defaultFullscreenFlag))
-	return true, sdlToGoError()
+	C.SDL_SetWindowIcon((*C.SDL_Window)(unsafe.Pointer(window)), surface)
+	C.SDL_FreeSurface(surface)
+	return false
+}
+
+func sdlSetWindowPosition(window uintptr, pos image.Point) {
+	C.SDL_SetWindowPosition((*C.SDL_Window)(unsafe.Pointer(window)), C.int(pos.X), C.int(pos.Y))
+}
+
+func sdlGetWindowPosition(window uintptr, x, y unsafe.Pointer) {
+	C.SDL_GetWindowPosition((*C.SDL_Window)(unsafe.Pointer(window)), (*C.int)(x), (*C.int)(y))
+}
+
+func sdlSetWindowSize(window uintptr, size image.Point) {
+	C.SDL_SetWindowSize((*C.SDL_Window)(unsafe.Pointer(window)), C.int(size.X), C.int(size.Y))
+}
+
+func sdlGetWindowSize(window uintptr, w, h unsafe.Pointer) {
+	C.SDL_GetWindowSize((*C.SDL_Window)(unsafe.Pointer(window)), (*C.int)(w), (*C.int)(h))
+}
+
+func sdlMinimizeWindow(window uintptr) {
+	C.SDL_MinimizeWindow((*C.SDL_Window)(unsafe.Pointer(window)))
+}
+
+func sdlMaximizeWindow(window uintptr) {
+	C.SDL_MaximizeWindow((*C.SDL_Window)(unsafe.Pointer(window)))
+}
+
+func sdlRestoreWindow(window uintptr) {
+	C.SDL_RestoreWindow((*C.SDL_Window)(unsafe.Pointer(window)))
 }
 
-func sdlCreateTexture(renderer uintptr, backBufferSize image.Point) uintptr {
-	return uintptr(unsafe.Pointer(C.SDL_CreateTexture((*C.SDL_Renderer)(unsafe.Pointer(renderer)), C.Uint32(pixelFormatABGR8888), C.int(1), C.int(backBufferSize.X), C.int(backBufferSize.Y))))
+func sdlRaiseWindow(window uintptr) {
+	C.SDL_RaiseWindow((*C.SDL_Window)(unsafe.Pointer(window)))
+}
+
+func sdlSetWindowBordered(window uintptr, b bool) {
+	C.SDL_SetWindowBordered((*C.SDL_Window)(unsafe.Pointer(window)), C.SDL_bool(sdlBool(b)))
+}
+
+func sdlSetWindowAlwaysOnTop(window uintptr, b bool) bool {
+	return C.vsdlSetWindowAlwaysOnTop((*C.SDL_Window)(unsafe.Pointer(window)), C.SDL_bool(sdlBool(b))) != 0
+}
+
+func sdlSetWindowOpacity(window uintptr, opacity float32) bool {
+	return C.SDL_SetWindowOpacity((*C.SDL_Window)(unsafe.Pointer(window)), C.float(opacity)) != 0
+}
+
+func sdlGetWindowOpacity(window uintptr, opacity unsafe.Pointer) bool {
+	return C.SDL_GetWindowOpacity((*C.SDL_Window)(unsafe.Pointer(window)), (*C.float)(opacity)) != 0
+}
+
+func sdlGetWindowDisplayMode(window uintptr, mode unsafe.Pointer) bool {
+	return C.SDL_GetWindowDisplayMode((*C.SDL_Window)(unsafe.Pointer(window)), (*C.SDL_DisplayMode)(mode)) != 0
+}
+
+func sdlSetWindowDisplayMode(window uintptr, mode unsafe.Pointer) bool {
+	return C.SDL_SetWindowDisplayMode((*C.SDL_Window)(unsafe.Pointer(window)), (*C.SDL_DisplayMode)(mode)) != 0
+}
+
+func sdlGetNumVideoDisplays() int {
+	return int(C.SDL_GetNumVideoDisplays())
+}
+
+func sdlGetDisplayName(index int) string {
+	name := C.SDL_GetDisplayName(C.int(index))
+	if name == nil {
+		return ""
+	}
+	return C.GoString(name)
+}
+
+func sdlGetDisplayBounds(index int, rect unsafe.Pointer) bool {
+	return C.SDL_GetDisplayBounds(C.int(index), (*C.SDL_Rect)(rect)) != 0
+}
+
+func sdlGetDisplayUsableBounds(index int, rect unsafe.Pointer) bool {
+	return C.SDL_GetDisplayUsableBounds(C.int(index), (*C.SDL_Rect)(rect)) != 0
+}
+
+func sdlGetDisplayDPI(index int, ddpi, hdpi, vdpi unsafe.Pointer) bool {
+	return C.SDL_GetDisplayDPI(C.int(index), (*C.float)(ddpi), (*C.float)(hdpi), (*C.float)(vdpi)) != 0
+}
+
+func sdlGetNumDisplayModes(index int) int {
+	return int(C.SDL_GetNumDisplayModes(C.int(index)))
+}
+
+func sdlGetDisplayMode(display, index int, mode unsafe.Pointer) bool {
+	return C.SDL_GetDisplayMode(C.int(display), C.int(index), (*C.SDL_DisplayMode)(mode)) != 0
+}
+
+func sdlGetDesktopDisplayMode(index int, mode unsafe.Pointer) bool {
+	return C.SDL_GetDesktopDisplayMode(C.int(index), (*C.SDL_DisplayMode)(mode)) != 0
+}
+
+func sdlGetClosestDisplayMode(index int, mode, closest unsafe.Pointer) bool {
+	return C.SDL_GetClosestDisplayMode(C.int(index), (*C.SDL_DisplayMode)(mode), (*C.SDL_DisplayMode)(closest)) == nil
+}
+
+func sdlSetWindowFullscreen(window uintptr, flags uint32) bool {
+	return C.SDL_SetWindowFullscreen((*C.SDL_Window)(unsafe.Pointer(window)), C.Uint32(flags)) != 0
+}
+
+func sdlCreateTexture(renderer uintptr, format uint32, backBufferSize image.Point) uintptr {
+	return uintptr(unsafe.Pointer(C.SDL_CreateTexture((*C.SDL_Renderer)(unsafe.Pointer(renderer)), C.Uint32(format), C.int(1), C.int(backBufferSize.X), C.int(backBufferSize.Y))))
 }
 
 func sdlDestroyTexture(texture uintptr) {
 	C.SDL_DestroyTexture((*C.SDL_Texture)(unsafe.Pointer(texture)))
 }
 
-func sdlUpdateTexture(texture, data, stride uintptr) bool {
-	return C.SDL_UpdateTexture((*C.SDL_Texture)(unsafe.Pointer(texture)), nil, unsafe.Pointer(data), C.int(stride)) != 0
+func sdlUpdateTexture(texture uintptr, rect, data unsafe.Pointer, stride uintptr) bool {
+	return C.SDL_UpdateTexture((*C.SDL_Texture)(unsafe.Pointer(texture)), (*C.SDL_Rect)(rect), data, C.int(stride)) != 0
+}
+
+func sdlUpdateYUVTexture(texture uintptr, rect, y unsafe.Pointer, yStride uintptr, u unsafe.Pointer, uStride uintptr, v unsafe.Pointer, vStride uintptr) bool {
+	return C.SDL_UpdateYUVTexture((*C.SDL_Texture)(unsafe.Pointer(texture)), (*C.SDL_Rect)(rect), (*C.Uint8)(y), C.int(yStride), (*C.Uint8)(u), C.int(uStride), (*C.Uint8)(v), C.int(vStride)) != 0
+}
+
+func sdlLockTexture(texture uintptr, pixels, pitch unsafe.Pointer) bool {
+	return C.SDL_LockTexture((*C.SDL_Texture)(unsafe.Pointer(texture)), nil, (*unsafe.Pointer)(pixels), (*C.int)(pitch)) != 0
+}
+
+func sdlUnlockTexture(texture uintptr) {
+	C.SDL_UnlockTexture((*C.SDL_Texture)(unsafe.Pointer(texture)))
 }
 
 func sdlRenderCopy(renderer, texture uintptr) bool {
@@ -123,6 +308,158 @@ func sdlRenderPresent(renderer uintptr) {
 	C.SDL_RenderPresent((*C.SDL_Renderer)(unsafe.Pointer(renderer)))
 }
 
-func sdlPollEvent(p uintptr) bool {
-	return C.SDL_PollEvent((*C.SDL_Event)(unsafe.Pointer(p))) != 0
+func sdlRenderReadPixels(renderer uintptr, rect unsafe.Pointer, format uint32, pixels unsafe.Pointer, pitch uintptr) bool {
+	return C.SDL_RenderReadPixels((*C.SDL_Renderer)(unsafe.Pointer(renderer)), (*C.SDL_Rect)(rect), C.Uint32(format), pixels, C.int(pitch)) != 0
+}
+
+func sdlGetRendererOutputSize(renderer uintptr, w, h unsafe.Pointer) bool {
+	return C.SDL_GetRendererOutputSize((*C.SDL_Renderer)(unsafe.Pointer(renderer)), (*C.int)(w), (*C.int)(h)) != 0
+}
+
+func sdlPollEvent(p unsafe.Pointer) bool {
+	return C.SDL_PollEvent((*C.SDL_Event)(p)) != 0
+}
+
+func sdlStartTextInput() {
+	C.SDL_StartTextInput()
+}
+
+func sdlStopTextInput() {
+	C.SDL_StopTextInput()
+}
+
+func sdlSetTextInputRect(rect unsafe.Pointer) {
+	C.SDL_SetTextInputRect((*C.SDL_Rect)(rect))
+}
+
+func sdlGetKeyFromScancode(s Scancode) Keycode {
+	return Keycode(C.SDL_GetKeyFromScancode(C.SDL_Scancode(s)))
+}
+
+func sdlGetKeyboardState() []uint8 {
+	var n C.int
+	state := C.SDL_GetKeyboardState(&n)
+	if n > numScancodes {
+		n = numScancodes
+	}
+	return (*[numScancodes]uint8)(unsafe.Pointer(state))[:n:n]
+}
+
+func sdlGetModState() uint16 {
+	return uint16(C.SDL_GetModState())
+}
+
+func sdlGetMouseState(x, y unsafe.Pointer) uint32 {
+	return uint32(C.SDL_GetMouseState((*C.int)(x), (*C.int)(y)))
+}
+
+func sdlNumJoysticks() int {
+	return int(C.SDL_NumJoysticks())
+}
+
+func sdlIsGameController(index int) bool {
+	return C.SDL_IsGameController(C.int(index)) != 0
+}
+
+func sdlGameControllerOpen(index int) uintptr {
+	return uintptr(unsafe.Pointer(C.SDL_GameControllerOpen(C.int(index))))
+}
+
+func sdlGameControllerClose(controller uintptr) {
+	C.SDL_GameControllerClose((*C.SDL_GameController)(unsafe.Pointer(controller)))
+}
+
+func sdlGameControllerName(controller uintptr) string {
+	return C.GoString(C.SDL_GameControllerName((*C.SDL_GameController)(unsafe.Pointer(controller))))
+}
+
+func sdlGameControllerGetJoystick(controller uintptr) uintptr {
+	return uintptr(unsafe.Pointer(C.SDL_GameControllerGetJoystick((*C.SDL_GameController)(unsafe.Pointer(controller)))))
+}
+
+func sdlGameControllerGetButton(controller uintptr, button ControllerButton) bool {
+	return C.SDL_GameControllerGetButton((*C.SDL_GameController)(unsafe.Pointer(controller)), C.SDL_GameControllerButton(button)) != 0
+}
+
+func sdlGameControllerGetAxis(controller uintptr, axis ControllerAxis) int16 {
+	return int16(C.SDL_GameControllerGetAxis((*C.SDL_GameController)(unsafe.Pointer(controller)), C.SDL_GameControllerAxis(axis)))
+}
+
+func sdlGameControllerAddMapping(mapping string) int {
+	str := C.CString(mapping)
+	defer C.free(unsafe.Pointer(str))
+	return int(C.SDL_GameControllerAddMapping(str))
+}
+
+func sdlJoystickInstanceID(joystick uintptr) int32 {
+	return int32(C.SDL_JoystickInstanceID((*C.SDL_Joystick)(unsafe.Pointer(joystick))))
+}
+
+func sdlJoystickGetGUID(joystick uintptr) [16]byte {
+	guid := C.SDL_JoystickGetGUID((*C.SDL_Joystick)(unsafe.Pointer(joystick)))
+	return *(*[16]byte)(unsafe.Pointer(&guid.data[0]))
+}
+
+func sdlGameControllerRumble(controller uintptr, low, high uint16, ms uint32) bool {
+	return C.vsdlGameControllerRumble((*C.SDL_GameController)(unsafe.Pointer(controller)), C.Uint16(low), C.Uint16(high), C.Uint32(ms)) != 0
+}
+
+func sdlGameControllerRumbleTriggers(controller uintptr, left, right uint16, ms uint32) bool {
+	return C.vsdlGameControllerRumbleTriggers((*C.SDL_GameController)(unsafe.Pointer(controller)), C.Uint16(left), C.Uint16(right), C.Uint32(ms)) != 0
+}
+
+func sdlGameControllerHasRumble(controller uintptr) int {
+	return int(C.vsdlGameControllerHasRumble((*C.SDL_GameController)(unsafe.Pointer(controller))))
+}
+
+func sdlGameControllerHasRumbleTriggers(controller uintptr) int {
+	return int(C.vsdlGameControllerHasRumbleTriggers((*C.SDL_GameController)(unsafe.Pointer(controller))))
+}
+
+func sdlAudioCallback() uintptr {
+	return uintptr(unsafe.Pointer(C.vsdlAudioCallback))
+}
+
+func sdlBool(b bool) C.int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func sdlGetNumAudioDevices(capture bool) int {
+	return int(C.SDL_GetNumAudioDevices(sdlBool(capture)))
+}
+
+func sdlGetAudioDeviceName(index int, capture bool) string {
+	return C.GoString(C.SDL_GetAudioDeviceName(C.int(index), sdlBool(capture)))
+}
+
+func sdlOpenAudioDevice(name string, capture bool, desired, obtained unsafe.Pointer) uint32 {
+	var str *C.char
+	if name != "" {
+		str = C.CString(name)
+		defer C.free(unsafe.Pointer(str))
+	}
+	return uint32(C.SDL_OpenAudioDevice(str, sdlBool(capture), (*C.SDL_AudioSpec)(desired), (*C.SDL_AudioSpec)(obtained), 0))
+}
+
+func sdlPauseAudioDevice(dev uint32, pause bool) {
+	C.SDL_PauseAudioDevice(C.SDL_AudioDeviceID(dev), sdlBool(pause))
+}
+
+func sdlCloseAudioDevice(dev uint32) {
+	C.SDL_CloseAudioDevice(C.SDL_AudioDeviceID(dev))
+}
+
+func sdlQueueAudio(dev uint32, data unsafe.Pointer, n int) bool {
+	return C.SDL_QueueAudio(C.SDL_AudioDeviceID(dev), data, C.Uint32(n)) != 0
+}
+
+func sdlGetQueuedAudioSize(dev uint32) int {
+	return int(C.SDL_GetQueuedAudioSize(C.SDL_AudioDeviceID(dev)))
+}
+
+func sdlClearQueuedAudio(dev uint32) {
+	C.SDL_ClearQueuedAudio(C.SDL_AudioDeviceID(dev))
 }
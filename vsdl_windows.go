@@ -1,3 +1,5 @@
+// +build !headless
+
 /* This Source Code Form is subject to the terms of the Mozilla Public
  * License, v. 2.0. If a copy of the MPL was not distributed with this
  * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
@@ -8,6 +10,7 @@ import (
 	"bytes"
 	"errors"
 	"image"
+	"math"
 	"os"
 	"syscall"
 	"unsafe"
@@ -44,21 +47,29 @@ func getProc(name string) (uintptr, error) {
 	return proc, nil
 }
 
+func cString(s string) []byte {
+	return append([]byte(s), 0)
+}
+
 func sdlToGoError() error {
 	ret, _, _ := syscall.Syscall(sdlGetErrorProc, 0, 0, 0, 0)
 	if ret == 0 {
 		return errors.New("unknown error")
 	}
 
-	var buf bytes.Buffer
+	return newError(errors.New(goString(ret)), "internal error")
+}
 
-	for {
-		p := (*byte)(unsafe.Pointer(ret))
-		if *p == 0 {
-			return newError(errors.New(buf.String()), "internal error")
-		}
-		buf.WriteByte(*p)
+func goString(p uintptr) string {
+	if p == 0 {
+		return ""
 	}
+
+	var buf bytes.Buffer
+	for ; *(*byte)(unsafe.Pointer(p)) != 0; p++ {
+		buf.WriteByte(*(*byte)(unsafe.Pointer(p)))
+	}
+	return buf.String()
 }
 
 func sdlGetVersion() [3]byte {
@@ -77,13 +88,25 @@ func sdlQuit() {
 	syscall.Syscall(sdlQuitProc, 0, 0, 0, 0)
 }
 
-func sdlCreateWindowAndRenderer(windowSize image.Point, windowPtr, rendererPtr uintptr) bool {
-	ret, _, _ := syscall.Syscall6(sdlCreateWindowAndRendererProc, 5, uintptr(windowSize.X), uintptr(windowSize.Y), 0, windowPtr, rendererPtr, 0)
-	if ret == 0 {
-		syscall.Syscall(sdlShowCursorProc, 1, 0, 0, 0)
-		return false
+func sdlCreateWindowAndRenderer(windowSize image.Point, display int, windowFlags, rendererFlags uint32, windowPtr, rendererPtr unsafe.Pointer) bool {
+	title := cString("")
+	pos := uintptr(sdl_WINDOWPOS_UNDEFINED | display)
+	w, _, _ := syscall.Syscall6(sdlCreateWindowProc, 6, uintptr(unsafe.Pointer(&title[0])), pos, pos, uintptr(windowSize.X), uintptr(windowSize.Y), uintptr(windowFlags))
+	if w == 0 {
+		return true
 	}
-	return true
+
+	r, _, _ := syscall.Syscall(sdlCreateRendererProc, 3, w, ^uintptr(0), uintptr(rendererFlags))
+	if r == 0 {
+		syscall.Syscall(sdlDestroyWindowProc, 1, w, 0, 0)
+		return true
+	}
+
+	*(*uintptr)(windowPtr) = w
+	*(*uintptr)(rendererPtr) = r
+
+	syscall.Syscall(sdlShowCursorProc, 1, 0, 0, 0)
+	return false
 }
 
 func sdlDestroyRendererAndWindow(window, renderer uintptr) {
@@ -100,21 +123,156 @@ func sdlRenderSetLogicalSize(renderer uintptr, logicalSize image.Point) bool {
 	return ret != 0
 }
 
-func sdlToggleFullscreen(window uintptr) (bool, error) {
-	flags, _, _ := syscall.Syscall(sdlGetWindowFlagsProc, 1, window, 0, 0)
-	isFullscreen := (uint32(flags) & sdl_WINDOW_FULLSCREEN) != 0
+func sdlGetWindowID(window uintptr) uint32 {
+	ret, _, _ := syscall.Syscall(sdlGetWindowIDProc, 1, window, 0, 0)
+	return uint32(ret)
+}
+
+func sdlGetWindowFlags(window uintptr) uint32 {
+	ret, _, _ := syscall.Syscall(sdlGetWindowFlagsProc, 1, window, 0, 0)
+	return uint32(ret)
+}
+
+func sdlSetWindowTitle(window uintptr, title string) {
+	str := cString(title)
+	syscall.Syscall(sdlSetWindowTitleProc, 2, window, uintptr(unsafe.Pointer(&str[0])), 0)
+}
+
+func sdlGetWindowTitle(window uintptr) string {
+	ret, _, _ := syscall.Syscall(sdlGetWindowTitleProc, 1, window, 0, 0)
+	return goString(ret)
+}
+
+func sdlSetWindowIcon(window uintptr, icon *image.RGBA) bool {
+	size := icon.Rect.Size()
+	surface, _, _ := syscall.Syscall6(sdlCreateRGBSurfaceWithFormatFromProc, 6, uintptr(unsafe.Pointer(&icon.Pix[0])), uintptr(size.X), uintptr(size.Y), 32, uintptr(icon.Stride), uintptr(pixelFormatABGR8888))
+	if surface == 0 {
+		return true
+	}
+
+	syscall.Syscall(sdlSetWindowIconProc, 2, window, surface, 0)
+	syscall.Syscall(sdlFreeSurfaceProc, 1, surface, 0, 0)
+	return false
+}
+
+func sdlSetWindowPosition(window uintptr, pos image.Point) {
+	syscall.Syscall(sdlSetWindowPositionProc, 3, window, uintptr(pos.X), uintptr(pos.Y))
+}
+
+func sdlGetWindowPosition(window uintptr, x, y unsafe.Pointer) {
+	syscall.Syscall(sdlGetWindowPositionProc, 3, window, uintptr(x), uintptr(y))
+}
+
+func sdlSetWindowSize(window uintptr, size image.Point) {
+	syscall.Syscall(sdlSetWindowSizeProc, 3, window, uintptr(size.X), uintptr(size.Y))
+}
+
+func sdlGetWindowSize(window uintptr, w, h unsafe.Pointer) {
+	syscall.Syscall(sdlGetWindowSizeProc, 3, window, uintptr(w), uintptr(h))
+}
+
+func sdlMinimizeWindow(window uintptr) {
+	syscall.Syscall(sdlMinimizeWindowProc, 1, window, 0, 0)
+}
+
+func sdlMaximizeWindow(window uintptr) {
+	syscall.Syscall(sdlMaximizeWindowProc, 1, window, 0, 0)
+}
+
+func sdlRestoreWindow(window uintptr) {
+	syscall.Syscall(sdlRestoreWindowProc, 1, window, 0, 0)
+}
+
+func sdlRaiseWindow(window uintptr) {
+	syscall.Syscall(sdlRaiseWindowProc, 1, window, 0, 0)
+}
+
+func sdlSetWindowBordered(window uintptr, b bool) {
+	syscall.Syscall(sdlSetWindowBorderedProc, 2, window, sdlBool(b), 0)
+}
 
-	if isFullscreen {
-		syscall.Syscall(sdlSetWindowFullscreenProc, 2, window, 0, 0)
-		return false, sdlToGoError()
+func sdlSetWindowAlwaysOnTop(window uintptr, b bool) bool {
+	if sdlSetWindowAlwaysOnTopProc == 0 {
+		return sdlUnsupported()
 	}
+	syscall.Syscall(sdlSetWindowAlwaysOnTopProc, 2, window, sdlBool(b), 0)
+	return false
+}
 
-	syscall.Syscall(sdlSetWindowFullscreenProc, 2, window, uintptr(defaultFullscreenFlag), uintptr(0))
-	return true, sdlToGoError()
+// The float argument is passed as its bit pattern, the syscall package also
+// loads the arguments into the floating point registers.
+func sdlSetWindowOpacity(window uintptr, opacity float32) bool {
+	ret, _, _ := syscall.Syscall(sdlSetWindowOpacityProc, 2, window, uintptr(math.Float32bits(opacity)), 0)
+	return int32(ret) != 0
 }
 
-func sdlCreateTexture(renderer uintptr, backBufferSize image.Point) uintptr {
-	texture, _, _ := syscall.Syscall6(sdlCreateTextureProc, 5, renderer, uintptr(pixelFormatABGR8888), 1, uintptr(backBufferSize.X), uintptr(backBufferSize.Y), 0)
+func sdlGetWindowOpacity(window uintptr, opacity unsafe.Pointer) bool {
+	ret, _, _ := syscall.Syscall(sdlGetWindowOpacityProc, 2, window, uintptr(opacity), 0)
+	return int32(ret) != 0
+}
+
+func sdlGetWindowDisplayMode(window uintptr, mode unsafe.Pointer) bool {
+	ret, _, _ := syscall.Syscall(sdlGetWindowDisplayModeProc, 2, window, uintptr(mode), 0)
+	return ret != 0
+}
+
+func sdlSetWindowDisplayMode(window uintptr, mode unsafe.Pointer) bool {
+	ret, _, _ := syscall.Syscall(sdlSetWindowDisplayModeProc, 2, window, uintptr(mode), 0)
+	return int32(ret) != 0
+}
+
+func sdlGetNumVideoDisplays() int {
+	ret, _, _ := syscall.Syscall(sdlGetNumVideoDisplaysProc, 0, 0, 0, 0)
+	return int(int32(ret))
+}
+
+func sdlGetDisplayName(index int) string {
+	ret, _, _ := syscall.Syscall(sdlGetDisplayNameProc, 1, uintptr(index), 0, 0)
+	return goString(ret)
+}
+
+func sdlGetDisplayBounds(index int, rect unsafe.Pointer) bool {
+	ret, _, _ := syscall.Syscall(sdlGetDisplayBoundsProc, 2, uintptr(index), uintptr(rect), 0)
+	return int32(ret) != 0
+}
+
+func sdlGetDisplayUsableBounds(index int, rect unsafe.Pointer) bool {
+	ret, _, _ := syscall.Syscall(sdlGetDisplayUsableBoundsProc, 2, uintptr(index), uintptr(rect), 0)
+	return int32(ret) != 0
+}
+
+func sdlGetDisplayDPI(index int, ddpi, hdpi, vdpi unsafe.Pointer) bool {
+	ret, _, _ := syscall.Syscall6(sdlGetDisplayDPIProc, 4, uintptr(index), uintptr(ddpi), uintptr(hdpi), uintptr(vdpi), 0, 0)
+	return int32(ret) != 0
+}
+
+func sdlGetNumDisplayModes(index int) int {
+	ret, _, _ := syscall.Syscall(sdlGetNumDisplayModesProc, 1, uintptr(index), 0, 0)
+	return int(int32(ret))
+}
+
+func sdlGetDisplayMode(display, index int, mode unsafe.Pointer) bool {
+	ret, _, _ := syscall.Syscall(sdlGetDisplayModeProc, 3, uintptr(display), uintptr(index), uintptr(mode))
+	return int32(ret) != 0
+}
+
+func sdlGetDesktopDisplayMode(index int, mode unsafe.Pointer) bool {
+	ret, _, _ := syscall.Syscall(sdlGetDesktopDisplayModeProc, 2, uintptr(index), uintptr(mode), 0)
+	return int32(ret) != 0
+}
+
+func sdlGetClosestDisplayMode(index int, mode, closest unsafe.Pointer) bool {
+	ret, _, _ := syscall.Syscall(sdlGetClosestDisplayModeProc, 3, uintptr(index), uintptr(mode), uintptr(closest))
+	return ret == 0
+}
+
+func sdlSetWindowFullscreen(window uintptr, flags uint32) bool {
+	ret, _, _ := syscall.Syscall(sdlSetWindowFullscreenProc, 2, window, uintptr(flags), 0)
+	return int32(ret) != 0
+}
+
+func sdlCreateTexture(renderer uintptr, format uint32, backBufferSize image.Point) uintptr {
+	texture, _, _ := syscall.Syscall6(sdlCreateTextureProc, 5, renderer, uintptr(format), 1, uintptr(backBufferSize.X), uintptr(backBufferSize.Y), 0)
 	return texture
 }
 
@@ -124,11 +282,25 @@ func sdlDestroyTexture(texture uintptr) {
 	}
 }
 
-func sdlUpdateTexture(texture, data, stride uintptr) bool {
-	ret, _, _ := syscall.Syscall6(sdlUpdateTextureProc, 4, texture, 0, data, stride, 0, 0)
+func sdlUpdateTexture(texture uintptr, rect, data unsafe.Pointer, stride uintptr) bool {
+	ret, _, _ := syscall.Syscall6(sdlUpdateTextureProc, 4, texture, uintptr(rect), uintptr(data), stride, 0, 0)
+	return ret != 0
+}
+
+func sdlUpdateYUVTexture(texture uintptr, rect, y unsafe.Pointer, yStride uintptr, u unsafe.Pointer, uStride uintptr, v unsafe.Pointer, vStride uintptr) bool {
+	ret, _, _ := syscall.Syscall9(sdlUpdateYUVTextureProc, 8, texture, uintptr(rect), uintptr(y), yStride, uintptr(u), uStride, uintptr(v), vStride, 0)
+	return ret != 0
+}
+
+func sdlLockTexture(texture uintptr, pixels, pitch unsafe.Pointer) bool {
+	ret, _, _ := syscall.Syscall6(sdlLockTextureProc, 4, texture, 0, uintptr(pixels), uintptr(pitch), 0, 0)
 	return ret != 0
 }
 
+func sdlUnlockTexture(texture uintptr) {
+	syscall.Syscall(sdlUnlockTextureProc, 1, texture, 0, 0)
+}
+
 func sdlRenderCopy(renderer, texture uintptr) bool {
 	ret, _, _ := syscall.Syscall6(sdlRenderCopyProc, 4, renderer, texture, 0, 0, 0, 0)
 	return ret != 0
@@ -138,7 +310,214 @@ func sdlRenderPresent(renderer uintptr) {
 	syscall.Syscall(sdlRenderPresentProc, 1, renderer, 0, 0)
 }
 
-func sdlPollEvent(p uintptr) bool {
-	ret, _, _ := syscall.Syscall(sdlPollEventProc, 1, p, 0, 0)
+func sdlRenderReadPixels(renderer uintptr, rect unsafe.Pointer, format uint32, pixels unsafe.Pointer, pitch uintptr) bool {
+	ret, _, _ := syscall.Syscall6(sdlRenderReadPixelsProc, 5, renderer, uintptr(rect), uintptr(format), uintptr(pixels), pitch, 0)
+	return ret != 0
+}
+
+func sdlGetRendererOutputSize(renderer uintptr, w, h unsafe.Pointer) bool {
+	ret, _, _ := syscall.Syscall(sdlGetRendererOutputSizeProc, 3, renderer, uintptr(w), uintptr(h))
 	return ret != 0
 }
+
+func sdlPollEvent(p unsafe.Pointer) bool {
+	ret, _, _ := syscall.Syscall(sdlPollEventProc, 1, uintptr(p), 0, 0)
+	return ret != 0
+}
+
+func sdlStartTextInput() {
+	syscall.Syscall(sdlStartTextInputProc, 0, 0, 0, 0)
+}
+
+func sdlStopTextInput() {
+	syscall.Syscall(sdlStopTextInputProc, 0, 0, 0, 0)
+}
+
+func sdlSetTextInputRect(rect unsafe.Pointer) {
+	syscall.Syscall(sdlSetTextInputRectProc, 1, uintptr(rect), 0, 0)
+}
+
+func sdlGetKeyFromScancode(s Scancode) Keycode {
+	ret, _, _ := syscall.Syscall(sdlGetKeyFromScancodeProc, 1, uintptr(s), 0, 0)
+	return Keycode(int32(ret))
+}
+
+var keyboardStateSize int32
+
+func sdlGetKeyboardState() []uint8 {
+	state, _, _ := syscall.Syscall(sdlGetKeyboardStateProc, 1, uintptr(unsafe.Pointer(&keyboardStateSize)), 0, 0)
+	n := keyboardStateSize
+	if n > numScancodes {
+		n = numScancodes
+	}
+	return (*[numScancodes]uint8)(unsafe.Pointer(state))[:n:n]
+}
+
+func sdlGetModState() uint16 {
+	ret, _, _ := syscall.Syscall(sdlGetModStateProc, 0, 0, 0, 0)
+	return uint16(ret)
+}
+
+func sdlGetMouseState(x, y unsafe.Pointer) uint32 {
+	ret, _, _ := syscall.Syscall(sdlGetMouseStateProc, 2, uintptr(x), uintptr(y), 0)
+	return uint32(ret)
+}
+
+func sdlNumJoysticks() int {
+	ret, _, _ := syscall.Syscall(sdlNumJoysticksProc, 0, 0, 0, 0)
+	return int(int32(ret))
+}
+
+func sdlIsGameController(index int) bool {
+	ret, _, _ := syscall.Syscall(sdlIsGameControllerProc, 1, uintptr(index), 0, 0)
+	return uint32(ret) != 0
+}
+
+func sdlGameControllerOpen(index int) uintptr {
+	ret, _, _ := syscall.Syscall(sdlGameControllerOpenProc, 1, uintptr(index), 0, 0)
+	return ret
+}
+
+func sdlGameControllerClose(controller uintptr) {
+	syscall.Syscall(sdlGameControllerCloseProc, 1, controller, 0, 0)
+}
+
+func sdlGameControllerName(controller uintptr) string {
+	ret, _, _ := syscall.Syscall(sdlGameControllerNameProc, 1, controller, 0, 0)
+	return goString(ret)
+}
+
+func sdlGameControllerGetJoystick(controller uintptr) uintptr {
+	ret, _, _ := syscall.Syscall(sdlGameControllerGetJoystickProc, 1, controller, 0, 0)
+	return ret
+}
+
+func sdlGameControllerGetButton(controller uintptr, button ControllerButton) bool {
+	ret, _, _ := syscall.Syscall(sdlGameControllerGetButtonProc, 2, controller, uintptr(button), 0)
+	return uint8(ret) != 0
+}
+
+func sdlGameControllerGetAxis(controller uintptr, axis ControllerAxis) int16 {
+	ret, _, _ := syscall.Syscall(sdlGameControllerGetAxisProc, 2, controller, uintptr(axis), 0)
+	return int16(ret)
+}
+
+func sdlGameControllerAddMapping(mapping string) int {
+	str := cString(mapping)
+	ret, _, _ := syscall.Syscall(sdlGameControllerAddMappingProc, 1, uintptr(unsafe.Pointer(&str[0])), 0, 0)
+	return int(int32(ret))
+}
+
+func sdlJoystickInstanceID(joystick uintptr) int32 {
+	ret, _, _ := syscall.Syscall(sdlJoystickInstanceIDProc, 1, joystick, 0, 0)
+	return int32(ret)
+}
+
+var joystickGUID [16]byte
+
+// SDL_JoystickGUID is returned through a hidden pointer argument.
+func sdlJoystickGetGUID(joystick uintptr) [16]byte {
+	syscall.Syscall(sdlJoystickGetGUIDProc, 2, uintptr(unsafe.Pointer(&joystickGUID)), joystick, 0)
+	return joystickGUID
+}
+
+// sdlUnsupported sets the SDL error like SDL_Unsupported.
+func sdlUnsupported() bool {
+	syscall.Syscall(sdlErrorProc, 1, sdl_UNSUPPORTED, 0, 0)
+	return true
+}
+
+func sdlGameControllerRumble(controller uintptr, low, high uint16, ms uint32) bool {
+	if sdlGameControllerRumbleProc == 0 {
+		return sdlUnsupported()
+	}
+	ret, _, _ := syscall.Syscall6(sdlGameControllerRumbleProc, 4, controller, uintptr(low), uintptr(high), uintptr(ms), 0, 0)
+	return int32(ret) != 0
+}
+
+func sdlGameControllerRumbleTriggers(controller uintptr, left, right uint16, ms uint32) bool {
+	if sdlGameControllerRumbleTriggersProc == 0 {
+		return sdlUnsupported()
+	}
+	ret, _, _ := syscall.Syscall6(sdlGameControllerRumbleTriggersProc, 4, controller, uintptr(left), uintptr(right), uintptr(ms), 0, 0)
+	return int32(ret) != 0
+}
+
+func sdlGameControllerHasRumble(controller uintptr) int {
+	if sdlGameControllerHasRumbleProc == 0 {
+		return -1
+	}
+	ret, _, _ := syscall.Syscall(sdlGameControllerHasRumbleProc, 1, controller, 0, 0)
+	return int(uint32(ret))
+}
+
+func sdlGameControllerHasRumbleTriggers(controller uintptr) int {
+	if sdlGameControllerHasRumbleTriggersProc == 0 {
+		return -1
+	}
+	ret, _, _ := syscall.Syscall(sdlGameControllerHasRumbleTriggersProc, 1, controller, 0, 0)
+	return int(uint32(ret))
+}
+
+var audioCallbackPtr uintptr
+
+func sdlAudioCallback() uintptr {
+	if audioCallbackPtr == 0 {
+		audioCallbackPtr = syscall.NewCallbackCDecl(func(userdata, stream, length uintptr) uintptr {
+			n := int(int32(length))
+			audioCallback(userdata, (*[1 << 30]byte)(unsafe.Pointer(stream))[:n:n])
+			return 0
+		})
+	}
+	return audioCallbackPtr
+}
+
+func sdlBool(b bool) uintptr {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func sdlGetNumAudioDevices(capture bool) int {
+	ret, _, _ := syscall.Syscall(sdlGetNumAudioDevicesProc, 1, sdlBool(capture), 0, 0)
+	return int(int32(ret))
+}
+
+func sdlGetAudioDeviceName(index int, capture bool) string {
+	ret, _, _ := syscall.Syscall(sdlGetAudioDeviceNameProc, 2, uintptr(index), sdlBool(capture), 0)
+	return goString(ret)
+}
+
+func sdlOpenAudioDevice(name string, capture bool, desired, obtained unsafe.Pointer) uint32 {
+	if name == "" {
+		ret, _, _ := syscall.Syscall6(sdlOpenAudioDeviceProc, 5, 0, sdlBool(capture), uintptr(desired), uintptr(obtained), 0, 0)
+		return uint32(ret)
+	}
+
+	str := cString(name)
+	ret, _, _ := syscall.Syscall6(sdlOpenAudioDeviceProc, 5, uintptr(unsafe.Pointer(&str[0])), sdlBool(capture), uintptr(desired), uintptr(obtained), 0, 0)
+	return uint32(ret)
+}
+
+func sdlPauseAudioDevice(dev uint32, pause bool) {
+	syscall.Syscall(sdlPauseAudioDeviceProc, 2, uintptr(dev), sdlBool(pause), 0)
+}
+
+func sdlCloseAudioDevice(dev uint32) {
+	syscall.Syscall(sdlCloseAudioDeviceProc, 1, uintptr(dev), 0, 0)
+}
+
+func sdlQueueAudio(dev uint32, data unsafe.Pointer, n int) bool {
+	ret, _, _ := syscall.Syscall(sdlQueueAudioProc, 3, uintptr(dev), uintptr(data), uintptr(n))
+	return int32(ret) != 0
+}
+
+func sdlGetQueuedAudioSize(dev uint32) int {
+	ret, _, _ := syscall.Syscall(sdlGetQueuedAudioSizeProc, 1, uintptr(dev), 0, 0)
+	return int(uint32(ret))
+}
+
+func sdlClearQueuedAudio(dev uint32) {
+	syscall.Syscall(sdlClearQueuedAudioProc, 1, uintptr(dev), 0, 0)
+}
@@ -0,0 +1,175 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"time"
+)
+
+// Sound is decoded audio, stored as interleaved float32 samples in the range
+// [-1, 1].
+type Sound struct {
+	Samples  []float32
+	Channels int
+	Freq     int
+}
+
+// Duration returns the length of the sound.
+func (s *Sound) Duration() time.Duration {
+	if s.Channels == 0 || s.Freq == 0 {
+		return 0
+	}
+	return time.Duration(len(s.Samples)/s.Channels) * time.Second / time.Duration(s.Freq)
+}
+
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xfffe
+)
+
+// LoadWAV decodes a RIFF WAVE stream. Integer PCM with 8, 16, 24 or 32 bits
+// per sample and 32 or 64 bit float samples are supported.
+func LoadWAV(r io.Reader) (*Sound, error) {
+	br := bufio.NewReader(r)
+
+	var header [12]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return nil, err
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, errors.New("invalid wav header")
+	}
+
+	var (
+		format, bits uint16
+		snd          Sound
+		haveFormat   bool
+	)
+
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(br, chunk[:]); err != nil {
+			if err == io.EOF {
+				return nil, errors.New("wav data chunk not found")
+			}
+			return nil, err
+		}
+
+		size := int64(binary.LittleEndian.Uint32(chunk[4:]))
+		data := io.LimitReader(br, size)
+
+		switch string(chunk[:4]) {
+		case "fmt ":
+			b, err := ioutil.ReadAll(data)
+			if err != nil {
+				return nil, err
+			}
+			if len(b) < 16 {
+				return nil, errors.New("invalid wav format chunk")
+			}
+
+			format = binary.LittleEndian.Uint16(b[0:])
+			snd.Channels = int(binary.LittleEndian.Uint16(b[2:]))
+			snd.Freq = int(binary.LittleEndian.Uint32(b[4:]))
+			bits = binary.LittleEndian.Uint16(b[14:])
+
+			if format == wavFormatExtensible {
+				if len(b) < 26 {
+					return nil, errors.New("invalid wav format chunk")
+				}
+				format = binary.LittleEndian.Uint16(b[24:])
+			}
+
+			if snd.Channels == 0 || snd.Freq == 0 {
+				return nil, errors.New("invalid wav format chunk")
+			}
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return nil, errors.New("wav data before format chunk")
+			}
+
+			b, err := ioutil.ReadAll(data)
+			if err != nil {
+				return nil, err
+			}
+			if snd.Samples, err = decodeWAVSamples(b, format, bits); err != nil {
+				return nil, err
+			}
+
+			// Drop a trailing partial frame.
+			snd.Samples = snd.Samples[:len(snd.Samples)/snd.Channels*snd.Channels]
+			return &snd, nil
+		default:
+			if _, err := io.Copy(ioutil.Discard, data); err != nil {
+				return nil, err
+			}
+		}
+
+		// Chunks are padded to an even size.
+		if size&1 != 0 {
+			if _, err := br.Discard(1); err != nil && err != io.EOF {
+				return nil, err
+			}
+		}
+	}
+}
+
+func LoadWAVFile(name string) (*Sound, error) {
+	fp, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	return LoadWAV(fp)
+}
+
+func decodeWAVSamples(b []byte, format, bits uint16) ([]float32, error) {
+	size := int(bits) / 8
+	if size == 0 {
+		return nil, errors.New("invalid wav sample size")
+	}
+
+	samples := make([]float32, len(b)/size)
+
+	switch {
+	case format == wavFormatPCM && bits == 8:
+		for i := range samples {
+			samples[i] = float32(int(b[i])-128) / 128
+		}
+	case format == wavFormatPCM && bits == 16:
+		for i := range samples {
+			samples[i] = float32(int16(binary.LittleEndian.Uint16(b[i*2:]))) / (1 << 15)
+		}
+	case format == wavFormatPCM && bits == 24:
+		for i := range samples {
+			v := int32(b[i*3])<<8 | int32(b[i*3+1])<<16 | int32(b[i*3+2])<<24
+			samples[i] = float32(v>>8) / (1 << 23)
+		}
+	case format == wavFormatPCM && bits == 32:
+		for i := range samples {
+			samples[i] = float32(int32(binary.LittleEndian.Uint32(b[i*4:]))) / (1 << 31)
+		}
+	case format == wavFormatFloat && bits == 32:
+		for i := range samples {
+			samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[i*4:]))
+		}
+	case format == wavFormatFloat && bits == 64:
+		for i := range samples {
+			samples[i] = float32(math.Float64frombits(binary.LittleEndian.Uint64(b[i*8:])))
+		}
+	default:
+		return nil, errors.New("unsupported wav sample format")
+	}
+	return samples, nil
+}
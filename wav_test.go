@@ -0,0 +1,126 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+type wavChunk struct {
+	id   string
+	data []byte
+}
+
+// buildWAV returns a RIFF WAVE stream with the chunks, odd sized chunks are
+// padded.
+func buildWAV(chunks ...wavChunk) []byte {
+	var body bytes.Buffer
+	body.WriteString("WAVE")
+	for _, c := range chunks {
+		body.WriteString(c.id)
+		binary.Write(&body, binary.LittleEndian, uint32(len(c.data)))
+		body.Write(c.data)
+		if len(c.data)&1 != 0 {
+			body.WriteByte(0)
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(body.Len()))
+	b.Write(body.Bytes())
+	return b.Bytes()
+}
+
+func wavFormat(format uint16, channels, freq, bits int) wavChunk {
+	var b bytes.Buffer
+	align := channels * bits / 8
+	for _, v := range []interface{}{format, uint16(channels), uint32(freq), uint32(freq * align), uint16(align), uint16(bits)} {
+		binary.Write(&b, binary.LittleEndian, v)
+	}
+	return wavChunk{"fmt ", b.Bytes()}
+}
+
+func wavExtensibleFormat(subFormat uint16, channels, freq, bits int) wavChunk {
+	c := wavFormat(wavFormatExtensible, channels, freq, bits)
+	ext := make([]byte, 24)
+	binary.LittleEndian.PutUint16(ext[0:], 22)
+	binary.LittleEndian.PutUint16(ext[2:], uint16(bits))
+	binary.LittleEndian.PutUint16(ext[8:], subFormat)
+	c.data = append(c.data, ext...)
+	return c
+}
+
+func wavData(values ...interface{}) wavChunk {
+	var b bytes.Buffer
+	for _, v := range values {
+		binary.Write(&b, binary.LittleEndian, v)
+	}
+	return wavChunk{"data", b.Bytes()}
+}
+
+func TestLoadWAV(t *testing.T) {
+	tests := []struct {
+		name     string
+		wav      []byte
+		channels int
+		want     []float32
+	}{
+		{"pcm 8-bit", buildWAV(wavFormat(wavFormatPCM, 1, 8000, 8), wavData(uint8(0x80), uint8(0xc0), uint8(0))), 1, []float32{0, 0.5, -1}},
+		{"pcm 16-bit", buildWAV(wavFormat(wavFormatPCM, 1, 8000, 16), wavData(int16(0), int16(1<<14), int16(-1<<15))), 1, []float32{0, 0.5, -1}},
+		{"pcm 24-bit", buildWAV(wavFormat(wavFormatPCM, 1, 8000, 24), wavChunk{"data", []byte{0, 0, 0, 0, 0, 0x40, 0, 0, 0x80}}), 1, []float32{0, 0.5, -1}},
+		{"pcm 32-bit", buildWAV(wavFormat(wavFormatPCM, 1, 8000, 32), wavData(int32(0), int32(1<<30), int32(-1<<31))), 1, []float32{0, 0.5, -1}},
+		{"float 32-bit", buildWAV(wavFormat(wavFormatFloat, 1, 8000, 32), wavData(float32(0.25), float32(-0.75))), 1, []float32{0.25, -0.75}},
+		{"float 64-bit", buildWAV(wavFormat(wavFormatFloat, 1, 8000, 64), wavData(0.25, -0.75)), 1, []float32{0.25, -0.75}},
+		{"extensible", buildWAV(wavExtensibleFormat(wavFormatFloat, 2, 8000, 32), wavData(float32(0.25), float32(-0.75))), 2, []float32{0.25, -0.75}},
+		{"odd sized chunk", buildWAV(wavFormat(wavFormatPCM, 1, 8000, 16), wavChunk{"LIST", []byte{1, 2, 3}}, wavData(int16(1<<14))), 1, []float32{0.5}},
+		{"partial frame", buildWAV(wavFormat(wavFormatPCM, 2, 8000, 16), wavData(int16(1<<14), int16(-1<<14), int16(1<<14))), 2, []float32{0.5, -0.5}},
+		{"partial sample", buildWAV(wavFormat(wavFormatPCM, 1, 8000, 16), wavChunk{"data", []byte{0, 0x40, 0}}), 1, []float32{0.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snd, err := LoadWAV(bytes.NewReader(tt.wav))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if snd.Channels != tt.channels || snd.Freq != 8000 {
+				t.Fatalf("sound has %d channels at %d Hz, expected %d channels at 8000 Hz", snd.Channels, snd.Freq, tt.channels)
+			}
+			if len(snd.Samples) != len(tt.want) {
+				t.Fatalf("%d samples were decoded, expected %d", len(snd.Samples), len(tt.want))
+			}
+			for i, s := range snd.Samples {
+				if math.Abs(float64(s-tt.want[i])) > 1e-6 {
+					t.Errorf("sample %d is %v, expected %v", i, s, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadWAVErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		wav  []byte
+	}{
+		{"not riff", []byte("RIFX\x04\x00\x00\x00WAVE")},
+		{"no data", buildWAV(wavFormat(wavFormatPCM, 1, 8000, 16))},
+		{"data before format", buildWAV(wavData(int16(0)), wavFormat(wavFormatPCM, 1, 8000, 16))},
+		{"unsupported format", buildWAV(wavFormat(2, 1, 8000, 4), wavData(int16(0)))},
+		{"no channels", buildWAV(wavFormat(wavFormatPCM, 0, 8000, 16), wavData(int16(0)))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadWAV(bytes.NewReader(tt.wav)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,538 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// windowConfig collects the settings of the window configs while a window is
+// created, see NewWindow.
+var (
+	windowConfig struct {
+		size, logicalSize image.Point
+		resizable         bool
+		display           int
+		displayMode       *DisplayMode
+		fullscreen        FullscreenMode
+		format            PixelFormat
+		palette           color.Palette
+		pipelineDepth     int
+		vsync             bool
+		targetFrameTime   time.Duration
+		frameHook         FrameHook
+		newWindow         bool
+	}
+	windowConfigLock sync.Mutex
+)
+
+func resetWindowConfig() {
+	windowConfig.size = image.Point{640, 480}
+	windowConfig.logicalSize = image.Point{}
+	windowConfig.resizable = false
+	windowConfig.display = 0
+	windowConfig.displayMode = nil
+	windowConfig.fullscreen = Windowed
+	windowConfig.format = PixelFormatABGR8888
+	windowConfig.palette = nil
+	windowConfig.pipelineDepth = 0
+	windowConfig.vsync = false
+	windowConfig.targetFrameTime = 0
+	windowConfig.frameHook = nil
+}
+
+var (
+	defaultWindow *Window
+	windows       = map[uint32]*Window{}
+)
+
+// Window owns an SDL window with its renderer and back-buffer texture. The
+// package level functions operate on the window created by Initialize.
+type Window struct {
+	id                        uint32
+	size, logicalSize         image.Point
+	sizeLock                  sync.Mutex
+	resizable, resizePending  bool
+	fullUploadPending         bool
+	window, renderer, texture uintptr
+	format                    PixelFormat
+
+	display        int
+	displayMode    *DisplayMode
+	fullscreen     FullscreenMode
+	fullscreenMode FullscreenMode
+	windowedBounds image.Rectangle
+
+	scratchBuffer *image.RGBA
+	scratchImage  draw.Image
+	lockedFrame   *image.RGBA
+
+	palette       [maxPaletteSize][4]uint8
+	devicePalette color.Palette
+	indexBuffer   *image.Paletted
+	expandBuffer  *image.RGBA
+
+	pipelineDepth   int
+	frameBuffers    chan image.Image
+	uploads         chan func()
+	pipelineErr     error
+	pipelineErrLock sync.Mutex
+
+	vsync           bool
+	targetFrameTime time.Duration
+	frameInterval   time.Duration
+	nextFrame       time.Time
+	pacingLock      sync.Mutex
+	lastFrame       time.Time
+	frameStats      FrameStatistics
+	frameStatsLock  sync.Mutex
+
+	frameHook     FrameHook
+	frameHookLock sync.Mutex
+}
+
+// errGlobalConfig is returned by the configs that change global state, such as
+// ConfigWithLibrary, when they are passed to NewWindow.
+var errGlobalConfig = errors.New("config is only accepted by Initialize")
+
+// NewWindow opens an additional window. Only the window related configs are
+// accepted, such as ConfigWithRenderer, ConfigWithPixelFormat and
+// ConfigWithVSync.
+func NewWindow(configs ...Config) (*Window, error) {
+	w, err := configureWindow(configs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sendCommand(false, w.create); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// configureWindow applies configs and returns a window with the resulting
+// window config, newWindow rejects the global configs.
+func configureWindow(configs []Config, newWindow bool) (*Window, error) {
+	windowConfigLock.Lock()
+	defer windowConfigLock.Unlock()
+
+	resetWindowConfig()
+	windowConfig.newWindow = newWindow
+	for _, cfg := range configs {
+		if err := cfg(); err != nil {
+			return nil, newError(err, "configuration error")
+		}
+	}
+
+	cfg := &windowConfig
+	w := &Window{
+		size:            cfg.size,
+		logicalSize:     cfg.logicalSize,
+		resizable:       cfg.resizable,
+		display:         cfg.display,
+		displayMode:     cfg.displayMode,
+		fullscreen:      cfg.fullscreen,
+		fullscreenMode:  FullscreenDesktop,
+		format:          cfg.format,
+		pipelineDepth:   cfg.pipelineDepth,
+		vsync:           cfg.vsync,
+		targetFrameTime: cfg.targetFrameTime,
+		frameHook:       cfg.frameHook,
+	}
+
+	if w.displayMode != nil {
+		w.fullscreenMode = FullscreenExclusive
+	}
+	if w.format == PixelFormatIndex8 {
+		w.setPalette(cfg.palette)
+	}
+	return w, nil
+}
+
+// create creates the SDL objects of the window, it must be called on the main
+// thread.
+func (w *Window) create() error {
+	var windowFlags, rendererFlags uint32
+	if w.resizable {
+		windowFlags |= sdl_WINDOW_RESIZABLE
+	}
+	windowFlags |= fullscreenFlags[w.fullscreen]
+	if w.vsync {
+		rendererFlags |= sdl_RENDERER_PRESENTVSYNC
+	}
+
+	if sdlCreateWindowAndRenderer(w.size, w.display, windowFlags, rendererFlags, unsafe.Pointer(&w.window), unsafe.Pointer(&w.renderer)) {
+		return sdlToGoError()
+	}
+
+	w.id = sdlGetWindowID(w.window)
+	windows[w.id] = w
+
+	if w.displayMode != nil {
+		displayMode = w.displayMode.toSDL()
+		if displayMode.W == 0 || displayMode.H == 0 {
+			displayMode.W, displayMode.H = int32(w.size.X), int32(w.size.Y)
+		}
+		if sdlGetClosestDisplayMode(w.display, unsafe.Pointer(&displayMode), unsafe.Pointer(&closestDisplayMode)) {
+			w.destroy()
+			return errors.New("no display mode matches the requested mode")
+		}
+		if sdlSetWindowDisplayMode(w.window, unsafe.Pointer(&closestDisplayMode)) {
+			err := sdlToGoError()
+			w.destroy()
+			return err
+		}
+	}
+	w.initFramePacing()
+
+	if w.logicalSize.X != 0 {
+		if sdlRenderSetLogicalSize(w.renderer, w.logicalSize) {
+			err := sdlToGoError()
+			w.destroy()
+			return err
+		}
+	}
+
+	if w.texture = sdlCreateTexture(w.renderer, sdlPixelFormats[w.format], w.backBufferSize()); w.texture == 0 {
+		err := sdlToGoError()
+		w.destroy()
+		return err
+	}
+
+	if w.pipelineDepth > 0 {
+		w.frameBuffers = make(chan image.Image, w.pipelineDepth)
+		w.uploads = make(chan func(), w.pipelineDepth)
+		for i := 0; i < w.pipelineDepth; i++ {
+			w.frameBuffers <- w.newFrameBuffer(w.backBufferSize())
+		}
+	}
+	return nil
+}
+
+func (w *Window) destroy() {
+	if w.texture != 0 {
+		sdlDestroyTexture(w.texture)
+	}
+	sdlDestroyRendererAndWindow(w.window, w.renderer)
+	delete(windows, w.id)
+
+	w.window, w.renderer, w.texture = 0, 0, 0
+	w.lockedFrame = nil
+}
+
+func destroyWindows() {
+	for _, w := range windows {
+		w.destroy()
+	}
+	defaultWindow = nil
+}
+
+var (
+	errWindowClosed = errors.New("window is closed")
+	errFrameLocked  = errors.New("frame is locked")
+)
+
+// ErrBackBufferResized is returned for frames that were submitted before the
+// back-buffer was resized. The frame is dropped and should be presented again
+// at the new BackBufferSize.
+var ErrBackBufferResized = errors.New("back-buffer was resized")
+
+// Close destroys the window, the window created by Initialize is destroyed
+// when Initialize returns.
+func (w *Window) Close() error {
+	return sendCommand(false, func() error {
+		if w.window == 0 {
+			return errWindowClosed
+		}
+		w.destroy()
+		return nil
+	})
+}
+
+// DefaultWindow returns the window created by Initialize.
+func DefaultWindow() *Window {
+	return defaultWindow
+}
+
+// ID returns the SDL window id, it matches the WindowID field of events.
+func (w *Window) ID() uint32 {
+	return w.id
+}
+
+// ToggleFullscreen switches between windowed and fullscreen, desktop
+// fullscreen is used unless a display mode is configured. It returns true if
+// the window became fullscreen.
+func (w *Window) ToggleFullscreen() (bool, error) {
+	var b bool
+	err := w.command(func() error {
+		mode := w.fullscreenMode
+		if sdlGetWindowFlags(w.window)&sdl_WINDOW_FULLSCREEN != 0 {
+			mode = Windowed
+		}
+		b = mode != Windowed
+		return w.setFullscreen(mode)
+	})
+	return b, err
+}
+
+func (w *Window) Present(img image.Image) (*sync.WaitGroup, error) {
+	return w.PresentRegions(img, []image.Rectangle{img.Bounds()})
+}
+
+// PresentRegions works like Present but only uploads the parts of img that
+// intersects with rects. The rectangles are given in the coordinate space of img.
+func (w *Window) PresentRegions(img image.Image, rects []image.Rectangle) (*sync.WaitGroup, error) {
+	return w.presentRegions(img, rects, true)
+}
+
+func (w *Window) presentRegions(img image.Image, rects []image.Rectangle, block bool) (*sync.WaitGroup, error) {
+	wg := new(sync.WaitGroup)
+	bounds := img.Bounds()
+
+	size, full := w.backBufferState()
+	if bounds.Size() != size {
+		return wg, errors.New("image is not the same size as the back-buffer")
+	}
+	if full {
+		rects = []image.Rectangle{bounds}
+	}
+
+	regions := w.clipRegions(bounds, rects)
+	if len(regions) == 0 {
+		return wg, nil
+	}
+
+	if w.frameBuffers != nil {
+		return w.presentPipelined(img, regions, full, block)
+	}
+
+	frame, err := w.toBackBufferFormat(img, regions)
+	if err != nil {
+		return wg, err
+	}
+	w.waitForFrame()
+
+	wg.Add(1)
+	return wg, sendCommand(false, func() error {
+		defer wg.Done()
+
+		if w.texture == 0 {
+			return errWindowClosed
+		}
+		if w.lockedFrame != nil {
+			return errFrameLocked
+		}
+		if !w.acceptsFrame(frame) {
+			return ErrBackBufferResized
+		}
+		if err := w.updateFrame(frame, regions); err != nil {
+			return err
+		}
+		w.frameUploaded(full)
+		w.callFrameHook(w.frameHookFunc(), img)
+		return w.renderTexture()
+	})
+}
+
+var (
+	lockedPixels unsafe.Pointer
+	lockedPitch  int32
+)
+
+// LockFrame returns an image that aliases the memory of the back-buffer
+// texture, allowing the frame to be drawn without an extra copy. The initial
+// content of the image is undefined so every pixel has to be written before
+// calling UnlockAndPresent. The image must not be used after that call.
+func (w *Window) LockFrame() (*image.RGBA, error) {
+	if w.format != PixelFormatABGR8888 {
+		return nil, errors.New("back-buffer format does not support locking")
+	}
+
+	var frame *image.RGBA
+	err := sendCommand(false, func() error {
+		if w.texture == 0 {
+			return errWindowClosed
+		}
+		if w.lockedFrame != nil {
+			return errors.New("frame is already locked")
+		}
+
+		if sdlLockTexture(w.texture, unsafe.Pointer(&lockedPixels), unsafe.Pointer(&lockedPitch)) {
+			return sdlToGoError()
+		}
+
+		size := w.backBufferSize()
+		n := int(lockedPitch) * size.Y
+
+		w.lockedFrame = &image.RGBA{
+			Pix:    (*[1 << 30]uint8)(lockedPixels)[:n:n],
+			Stride: int(lockedPitch),
+			Rect:   image.Rectangle{Max: size},
+		}
+		frame = w.lockedFrame
+		return nil
+	})
+	return frame, err
+}
+
+// UnlockAndPresent submits the frame returned by LockFrame.
+func (w *Window) UnlockAndPresent() error {
+	w.waitForFrame()
+
+	return sendCommand(false, func() error {
+		if w.lockedFrame == nil {
+			return errors.New("frame is not locked")
+		}
+
+		// The locked memory is only valid until the texture is unlocked.
+		w.callFrameHook(w.frameHookFunc(), w.lockedFrame)
+		sdlUnlockTexture(w.texture)
+		w.lockedFrame = nil
+		w.frameUploaded(true)
+		err := w.renderTexture()
+
+		if w.resizePending {
+			if err := w.resize(w.backBufferSize()); err != nil {
+				return err
+			}
+		}
+		return err
+	})
+}
+
+// BackBufferSize returns the size of the images accepted by Present. It is the
+// logical size if one is set, otherwise the size of the window.
+func BackBufferSize() image.Point {
+	if defaultWindow == nil {
+		return image.Point{}
+	}
+	return defaultWindow.BackBufferSize()
+}
+
+func (w *Window) BackBufferSize() image.Point {
+	return w.backBufferSize()
+}
+
+func (w *Window) backBufferSize() image.Point {
+	size, _ := w.backBufferState()
+	return size
+}
+
+// backBufferState returns the back-buffer size and if the next frame has to be
+// uploaded in full, because the content of a recreated texture is undefined.
+func (w *Window) backBufferState() (image.Point, bool) {
+	if w.logicalSize.X != 0 {
+		return w.logicalSize, false
+	}
+
+	w.sizeLock.Lock()
+	defer w.sizeLock.Unlock()
+	return w.size, w.fullUploadPending
+}
+
+// frameUploaded is called on the main thread after a frame was uploaded, full
+// reports if the whole frame was.
+func (w *Window) frameUploaded(full bool) {
+	if !full {
+		return
+	}
+
+	w.sizeLock.Lock()
+	w.fullUploadPending = false
+	w.sizeLock.Unlock()
+}
+
+// acceptsFrame reports if frame matches the size of the texture, frames are
+// dropped if the window was resized after they were submitted.
+func (w *Window) acceptsFrame(frame image.Image) bool {
+	return !w.resizePending && frame.Bounds().Size() == w.backBufferSize()
+}
+
+// handleWindowEvent is called on the main thread for every window event.
+func handleWindowEvent(ev *WindowEvent) {
+	w := windows[ev.WindowID]
+	if w == nil || ev.Event != WindowSizeChanged {
+		return
+	}
+
+	if err := w.resize(ev.Size()); err != nil {
+		log.Println(err)
+	}
+}
+
+// resize recreates the back-buffer texture of a resizable window with the new
+// window size, unless a logical size is set. A locked frame delays this until it is presented.
+func (w *Window) resize(size image.Point) error {
+	if !w.resizable || w.logicalSize.X != 0 || size.X <= 0 || size.Y <= 0 {
+		return nil
+	}
+
+	w.sizeLock.Lock()
+	if size == w.size && !w.resizePending {
+		w.sizeLock.Unlock()
+		return nil
+	}
+	w.size = size
+	w.fullUploadPending = true
+	w.sizeLock.Unlock()
+
+	if w.lockedFrame != nil {
+		w.resizePending = true
+		return nil
+	}
+	w.resizePending = false
+
+	texture := sdlCreateTexture(w.renderer, sdlPixelFormats[w.format], size)
+	if texture == 0 {
+		return sdlToGoError()
+	}
+	sdlDestroyTexture(w.texture)
+	w.texture = texture
+	return nil
+}
+
+// clipRegions clips rects to bounds and translates them to be relative to the
+// bounds origin. YUV formats are aligned to the chroma subsampling.
+func (w *Window) clipRegions(bounds image.Rectangle, rects []image.Rectangle) []image.Rectangle {
+	size := image.Rectangle{Max: bounds.Size()}
+	regions := make([]image.Rectangle, 0, len(rects))
+
+	for _, r := range rects {
+		r = r.Intersect(bounds).Sub(bounds.Min)
+		if w.format == PixelFormatYV12 || w.format == PixelFormatIYUV {
+			r.Min.X, r.Min.Y = r.Min.X&^1, r.Min.Y&^1
+			r.Max.X, r.Max.Y = r.Max.X+r.Max.X&1, r.Max.Y+r.Max.Y&1
+			r = r.Intersect(size)
+		}
+		if !r.Empty() {
+			regions = append(regions, r)
+		}
+	}
+	return regions
+}
+
+// renderTexture presents the back-buffer texture as a new frame.
+func (w *Window) renderTexture() error {
+	if err := w.redraw(); err != nil {
+		return err
+	}
+	w.updateFrameStats()
+	return nil
+}
+
+// redraw presents the back-buffer texture without counting it as a frame.
+func (w *Window) redraw() error {
+	if sdlRenderCopy(w.renderer, w.texture) {
+		return sdlToGoError()
+	}
+	sdlRenderPresent(w.renderer)
+	return nil
+}
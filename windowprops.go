@@ -0,0 +1,194 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package vsdl
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"unsafe"
+)
+
+var (
+	windowCoords  [2]int32
+	windowOpacity float32
+)
+
+// command runs f on the main thread if the window is open.
+func (w *Window) command(f func() error) error {
+	return sendCommand(false, func() error {
+		if w.window == 0 {
+			return errWindowClosed
+		}
+		return f()
+	})
+}
+
+func (w *Window) SetTitle(title string) error {
+	return w.command(func() error {
+		sdlSetWindowTitle(w.window, title)
+		return nil
+	})
+}
+
+func (w *Window) Title() (string, error) {
+	var title string
+	err := w.command(func() error {
+		title = sdlGetWindowTitle(w.window)
+		return nil
+	})
+	return title, err
+}
+
+// SetIcon sets the window icon, the image is converted to RGBA.
+func (w *Window) SetIcon(img image.Image) error {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return errors.New("icon is empty")
+	}
+
+	icon := image.NewRGBA(image.Rectangle{Max: bounds.Size()})
+	draw.Draw(icon, icon.Rect, img, bounds.Min, draw.Src)
+
+	return w.command(func() error {
+		if sdlSetWindowIcon(w.window, icon) {
+			return sdlToGoError()
+		}
+		return nil
+	})
+}
+
+func (w *Window) SetPosition(pos image.Point) error {
+	return w.command(func() error {
+		sdlSetWindowPosition(w.window, pos)
+		return nil
+	})
+}
+
+func (w *Window) Position() (image.Point, error) {
+	var pos image.Point
+	err := w.command(func() error {
+		sdlGetWindowPosition(w.window, unsafe.Pointer(&windowCoords[0]), unsafe.Pointer(&windowCoords[1]))
+		pos = image.Pt(int(windowCoords[0]), int(windowCoords[1]))
+		return nil
+	})
+	return pos, err
+}
+
+// SetSize sets the size of the client area of the window. Unless the window is
+// resizable without a logical size, the back-buffer keeps its size and is
+// scaled to the window.
+func (w *Window) SetSize(size image.Point) error {
+	return w.command(func() error {
+		sdlSetWindowSize(w.window, size)
+		return nil
+	})
+}
+
+func (w *Window) Size() (image.Point, error) {
+	var size image.Point
+	err := w.command(func() error {
+		sdlGetWindowSize(w.window, unsafe.Pointer(&windowCoords[0]), unsafe.Pointer(&windowCoords[1]))
+		size = image.Pt(int(windowCoords[0]), int(windowCoords[1]))
+		return nil
+	})
+	return size, err
+}
+
+func (w *Window) Minimize() error {
+	return w.command(func() error {
+		sdlMinimizeWindow(w.window)
+		return nil
+	})
+}
+
+func (w *Window) Maximize() error {
+	return w.command(func() error {
+		sdlMaximizeWindow(w.window)
+		return nil
+	})
+}
+
+// Restore restores the size and position of a minimized or maximized window.
+func (w *Window) Restore() error {
+	return w.command(func() error {
+		sdlRestoreWindow(w.window)
+		return nil
+	})
+}
+
+// Raise raises the window above other windows and sets the input focus.
+func (w *Window) Raise() error {
+	return w.command(func() error {
+		sdlRaiseWindow(w.window)
+		return nil
+	})
+}
+
+func (w *Window) IsMinimized() (bool, error) {
+	return w.hasFlags(sdl_WINDOW_MINIMIZED)
+}
+
+func (w *Window) IsMaximized() (bool, error) {
+	return w.hasFlags(sdl_WINDOW_MAXIMIZED)
+}
+
+func (w *Window) SetBordered(b bool) error {
+	return w.command(func() error {
+		sdlSetWindowBordered(w.window, b)
+		return nil
+	})
+}
+
+func (w *Window) IsBordered() (bool, error) {
+	b, err := w.hasFlags(sdl_WINDOW_BORDERLESS)
+	return !b, err
+}
+
+// SetAlwaysOnTop requires SDL 2.0.16 or later.
+func (w *Window) SetAlwaysOnTop(b bool) error {
+	return w.command(func() error {
+		if sdlSetWindowAlwaysOnTop(w.window, b) {
+			return sdlToGoError()
+		}
+		return nil
+	})
+}
+
+func (w *Window) IsAlwaysOnTop() (bool, error) {
+	return w.hasFlags(sdl_WINDOW_ALWAYS_ON_TOP)
+}
+
+// SetOpacity sets the window opacity from 0, transparent, to 1, opaque. An
+// error is returned if the platform does not support it.
+func (w *Window) SetOpacity(opacity float32) error {
+	return w.command(func() error {
+		if sdlSetWindowOpacity(w.window, opacity) {
+			return sdlToGoError()
+		}
+		return nil
+	})
+}
+
+func (w *Window) Opacity() (float32, error) {
+	var opacity float32
+	err := w.command(func() error {
+		if sdlGetWindowOpacity(w.window, unsafe.Pointer(&windowOpacity)) {
+			return sdlToGoError()
+		}
+		opacity = windowOpacity
+		return nil
+	})
+	return opacity, err
+}
+
+func (w *Window) hasFlags(flags uint32) (bool, error) {
+	var b bool
+	err := w.command(func() error {
+		b = sdlGetWindowFlags(w.window)&flags == flags
+		return nil
+	})
+	return b, err
+}